@@ -5,6 +5,11 @@ import (
 	"amway/config"
 	"amway/handler/amway"
 	"amway/handler/my"
+	"amway/health"
+	"amway/httpserver"
+	"amway/logger"
+	"amway/metrics"
+	"amway/outbox"
 	"log"
 	"os"
 	"os/signal"
@@ -22,6 +27,21 @@ func Start() {
 		log.Printf("加载配置文件时出错: %v", err)
 		return
 	}
+	logger.Setup()
+
+	httpserver.Handle("/healthz", health.LivenessHandler())
+	httpserver.Handle("/readyz", health.ReadinessHandler())
+	if config.Cfg.HTTP.Metrics {
+		httpserver.Handle("/metrics", metrics.Handler())
+	}
+	httpserver.Start()
+
+	if err := config.Validate(); err != nil {
+		// HTTP 服务保持运行，/readyz 会报告配置未通过校验
+		log.Printf("配置校验失败: %v", err)
+		return
+	}
+	health.SetConfigValidated()
 
 	// 注册 amway 处理程序
 	amway.RegisterHandlers()
@@ -34,6 +54,9 @@ func Start() {
 		return
 	}
 
+	// 统计 Discord REST 请求的错误码
+	dg.Client.Transport = metrics.WrapTransport(dg.Client.Transport)
+
 	registerEventHandlers(dg)
 
 	err = dg.Open()
@@ -42,13 +65,16 @@ func Start() {
 		return
 	}
 
-	for _, guildID := range config.Cfg.Commands.Allowguils {
-		for _, cmd := range command.AllCommands {
-			_, err := dg.ApplicationCommandCreate(dg.State.User.ID, guildID, cmd)
-			if err != nil {
-				log.Fatalf("Cannot create '%v' command: %v", cmd.Name, err)
-			}
-		}
+	// 为 outbox worker 注入会话，开始处理积压的副作用
+	outbox.SetSession(dg)
+	outbox.Kick()
+	health.SetSession(dg)
+
+	// 同步斜杠命令，单个服务器失败不影响其他服务器
+	if command.LogSyncResults(command.SyncConfigured(dg)) {
+		health.SetCommandsRegistered()
+	} else {
+		log.Printf("部分命令同步失败，可通过 /amway_admin 的同步命令操作重试")
 	}
 
 	log.Printf("Bot is now running. Press CTRL-C to exit.")
@@ -56,6 +82,7 @@ func Start() {
 	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
 	<-sc
 
+	httpserver.Stop()
 	dg.Close()
 }
 
@@ -0,0 +1,213 @@
+package bulk
+
+import (
+	"amway/config"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Task 是一次 Discord REST 调用或一组相关调用
+type Task struct {
+	// Name 用于在失败列表中标识任务，例如投稿 ID
+	Name string
+	// Route 标识任务命中的 Discord 路由，例如 "messages:<channelID>"；
+	// 同一路由类型（冒号之前的部分）共享并发限制
+	Route string
+	Run   func(ctx context.Context) error
+}
+
+// Failure 记录单个失败任务
+type Failure struct {
+	Name string
+	Err  error
+}
+
+// Progress 是执行进度的快照
+type Progress struct {
+	Total     int
+	Done      int
+	Succeeded int
+	Failed    int
+}
+
+// Result 是批量执行的最终结果
+type Result struct {
+	Progress
+	Canceled bool
+	Failures []Failure
+}
+
+// Options 控制队列的节奏
+type Options struct {
+	// Interval 是两次任务启动之间的最小间隔
+	Interval time.Duration
+	// DefaultConcurrency 是未单独配置的路由类型的并发上限
+	DefaultConcurrency int
+	// RouteConcurrency 按路由类型配置并发上限
+	RouteConcurrency map[string]int
+	// OnProgress 在每个任务完成后被调用，调用是串行的
+	OnProgress func(Progress)
+}
+
+// Queue 按节奏执行批量任务，避免短时间内大量请求 Discord API
+type Queue struct {
+	opts Options
+
+	mu   sync.Mutex
+	sems map[string]chan struct{} // 按路由类型索引
+}
+
+// New 创建一个新的批量队列
+func New(opts Options) *Queue {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	if opts.DefaultConcurrency <= 0 {
+		opts.DefaultConcurrency = 1
+	}
+	return &Queue{opts: opts, sems: make(map[string]chan struct{})}
+}
+
+func routeKind(route string) string {
+	if idx := strings.Index(route, ":"); idx != -1 {
+		return route[:idx]
+	}
+	return route
+}
+
+// semaphore 返回路由类型共享的信号量，同一类型的不同路由（如不同频道）共用一个并发上限
+func (q *Queue) semaphore(route string) chan struct{} {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	kind := routeKind(route)
+	if sem, ok := q.sems[kind]; ok {
+		return sem
+	}
+	limit := q.opts.DefaultConcurrency
+	if n, ok := q.opts.RouteConcurrency[kind]; ok && n > 0 {
+		limit = n
+	}
+	sem := make(chan struct{}, limit)
+	q.sems[kind] = sem
+	return sem
+}
+
+// Run 执行所有任务并阻塞直到完成或 ctx 被取消；取消后未启动的任务不会再执行
+func (q *Queue) Run(ctx context.Context, tasks []Task) Result {
+	result := Result{Progress: Progress{Total: len(tasks)}}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	record := func(task Task, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		result.Done++
+		if err != nil {
+			result.Failed++
+			result.Failures = append(result.Failures, Failure{Name: task.Name, Err: err})
+		} else {
+			result.Succeeded++
+		}
+		if q.opts.OnProgress != nil {
+			q.opts.OnProgress(result.Progress)
+		}
+	}
+
+	pace := time.NewTicker(q.opts.Interval)
+	defer pace.Stop()
+
+dispatch:
+	for idx, task := range tasks {
+		if idx > 0 {
+			select {
+			case <-ctx.Done():
+				break dispatch
+			case <-pace.C:
+			}
+		}
+
+		sem := q.semaphore(task.Route)
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(task Task) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := func() (err error) {
+				defer func() {
+					if r := recover(); r != nil {
+						err = fmt.Errorf("panic: %v", r)
+					}
+				}()
+				return task.Run(ctx)
+			}()
+			record(task, err)
+		}(task)
+	}
+
+	wg.Wait()
+	result.Canceled = ctx.Err() != nil
+	return result
+}
+
+var (
+	jobsMu sync.Mutex
+	jobs   = make(map[string]context.CancelFunc)
+)
+
+// Track 为一次批量操作创建可取消的上下文，并返回用于取消按钮的 ID；
+// 操作结束后必须调用 done
+func Track(parent context.Context) (ctx context.Context, id string, done func()) {
+	ctx, cancel := context.WithCancel(parent)
+	id = uuid.New().String()
+
+	jobsMu.Lock()
+	jobs[id] = cancel
+	jobsMu.Unlock()
+
+	return ctx, id, func() {
+		jobsMu.Lock()
+		delete(jobs, id)
+		jobsMu.Unlock()
+		cancel()
+	}
+}
+
+// Cancel 取消正在进行的批量操作，如果操作不存在或已结束则返回 false
+func Cancel(id string) bool {
+	jobsMu.Lock()
+	cancel, ok := jobs[id]
+	jobsMu.Unlock()
+
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// NewFromConfig 按 config.yaml 中的 bulk_queue 配置创建队列
+func NewFromConfig(onProgress func(Progress)) *Queue {
+	cfg := config.Cfg.BulkQueue
+	interval, err := time.ParseDuration(cfg.Interval)
+	if err != nil {
+		interval = 0 // 使用默认值
+	}
+	return New(Options{
+		Interval:           interval,
+		DefaultConcurrency: cfg.DefaultConcurrency,
+		RouteConcurrency:   cfg.RouteConcurrency,
+		OnProgress:         onProgress,
+	})
+}
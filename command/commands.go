@@ -11,6 +11,8 @@ var AllCommands = []*discordgo.ApplicationCommand{
 	def.AmwayAdminCommand,
 	def.CreatePanelCommand,
 	def.LookupCommand,
+	def.ReasonTemplateCommand,
 	def.RebuildCommand,
+	def.ReviewCommand,
 	def.TestAssignRoleCommand,
 }
@@ -32,6 +32,10 @@ var AmwayAdminCommand = &discordgo.ApplicationCommand{
 					Name:  "重新发送",
 					Value: "resend",
 				},
+				{
+					Name:  "重新审核",
+					Value: "reopen",
+				},
 				{
 					Name:  "封禁",
 					Value: "ban",
@@ -40,12 +44,36 @@ var AmwayAdminCommand = &discordgo.ApplicationCommand{
 					Name:  "解除封禁",
 					Value: "lift_ban",
 				},
+				{
+					Name:  "用户备注",
+					Value: "user_note",
+				},
+				{
+					Name:  "任务列表",
+					Value: "jobs",
+				},
+				{
+					Name:  "运行任务",
+					Value: "run_job",
+				},
+				{
+					Name:  "待执行动作",
+					Value: "outbox",
+				},
+				{
+					Name:  "重试动作",
+					Value: "retry_outbox",
+				},
+				{
+					Name:  "同步命令",
+					Value: "sync_commands",
+				},
 			},
 		},
 		{
 			Type:        discordgo.ApplicationCommandOptionString,
 			Name:        "input",
-			Description: "投稿ID",
+			Description: "投稿ID / 任务名称 / 动作ID / 备注内容",
 			NameLocalizations: map[discordgo.Locale]string{
 				discordgo.ChineseCN: "输入",
 			},
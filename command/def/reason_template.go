@@ -0,0 +1,89 @@
+package def
+
+import "github.com/bwmarrin/discordgo"
+
+var ReasonTemplateCommand = &discordgo.ApplicationCommand{
+	Name:        "reason_template",
+	Description: "管理本服务器的不通过/封禁理由模板",
+	NameLocalizations: &map[discordgo.Locale]string{
+		discordgo.ChineseCN: "理由模板",
+	},
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "add",
+			Description: "添加理由模板",
+			NameLocalizations: map[discordgo.Locale]string{
+				discordgo.ChineseCN: "添加",
+			},
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "kind",
+					Description: "模板适用的结果",
+					NameLocalizations: map[discordgo.Locale]string{
+						discordgo.ChineseCN: "类型",
+					},
+					Required: true,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{
+							Name:  "不通过",
+							Value: "reject",
+						},
+						{
+							Name:  "封禁",
+							Value: "ban",
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "label",
+					Description: "在菜单中显示的简短标签",
+					NameLocalizations: map[discordgo.Locale]string{
+						discordgo.ChineseCN: "标签",
+					},
+					Required:  true,
+					MaxLength: 50,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "text",
+					Description: "发送给用户的理由全文 (最多 850 字，为审核员的补充说明留出空间)",
+					NameLocalizations: map[discordgo.Locale]string{
+						discordgo.ChineseCN: "全文",
+					},
+					Required:  true,
+					MaxLength: 850,
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "remove",
+			Description: "删除理由模板",
+			NameLocalizations: map[discordgo.Locale]string{
+				discordgo.ChineseCN: "删除",
+			},
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "id",
+					Description: "模板编号，可在列表中查看",
+					NameLocalizations: map[discordgo.Locale]string{
+						discordgo.ChineseCN: "编号",
+					},
+					Required: true,
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "list",
+			Description: "查看理由模板及其使用次数",
+			NameLocalizations: map[discordgo.Locale]string{
+				discordgo.ChineseCN: "列表",
+			},
+		},
+	},
+}
@@ -0,0 +1,104 @@
+package def
+
+import "github.com/bwmarrin/discordgo"
+
+var minStatsDays = 1.0
+
+var minAwayHours = 1.0
+
+var ReviewCommand = &discordgo.ApplicationCommand{
+	Name:        "review",
+	Description: "审核员工具",
+	NameLocalizations: &map[discordgo.Locale]string{
+		discordgo.ChineseCN: "审核",
+	},
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "queue",
+			Description: "按等待时间列出待审核的投稿",
+			NameLocalizations: map[discordgo.Locale]string{
+				discordgo.ChineseCN: "队列",
+			},
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "unvoted",
+					Description: "只显示我尚未投票的投稿",
+					NameLocalizations: map[discordgo.Locale]string{
+						discordgo.ChineseCN: "仅未投票",
+					},
+					Required: false,
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "stats",
+			Description: "查看审核员在一段时间内的投票统计",
+			NameLocalizations: map[discordgo.Locale]string{
+				discordgo.ChineseCN: "统计",
+			},
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "days",
+					Description: "统计最近多少天内的投票 (默认 30)",
+					NameLocalizations: map[discordgo.Locale]string{
+						discordgo.ChineseCN: "天数",
+					},
+					Required: false,
+					MinValue: &minStatsDays,
+					MaxValue: 365,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionUser,
+					Name:        "user",
+					Description: "要查看的审核员 (默认为自己，查看他人需要管理员权限)",
+					NameLocalizations: map[discordgo.Locale]string{
+						discordgo.ChineseCN: "用户",
+					},
+					Required: false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "leaderboard",
+					Description: "查看全服审核排行榜 (需要管理员权限)",
+					NameLocalizations: map[discordgo.Locale]string{
+						discordgo.ChineseCN: "排行榜",
+					},
+					Required: false,
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "away",
+			Description: "暂停接受审核指派",
+			NameLocalizations: map[discordgo.Locale]string{
+				discordgo.ChineseCN: "暂离",
+			},
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "hours",
+					Description: "暂停多少小时 (不填则直到使用 /review back 恢复)",
+					NameLocalizations: map[discordgo.Locale]string{
+						discordgo.ChineseCN: "小时",
+					},
+					Required: false,
+					MinValue: &minAwayHours,
+					MaxValue: 720,
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "back",
+			Description: "恢复接受审核指派",
+			NameLocalizations: map[discordgo.Locale]string{
+				discordgo.ChineseCN: "恢复",
+			},
+		},
+	},
+}
@@ -0,0 +1,229 @@
+package command
+
+import (
+	"amway/bulk"
+	"amway/command/def"
+	"amway/config"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// GlobalScope 表示全局命令（不属于任何服务器）
+const GlobalScope = ""
+
+// DevCommands 是仅用于开发调试的命令，可在生产环境中通过 commands.skip_dev_commands 跳过
+var DevCommands = map[string]bool{
+	def.TestAssignRoleCommand.Name: true,
+}
+
+// SyncResult 记录一个作用域（服务器或全局）的同步结果
+type SyncResult struct {
+	GuildID   string
+	Created   []string
+	Updated   []string
+	Removed   []string
+	Unchanged []string
+	Err       error
+}
+
+// Changed 判断该作用域的命令是否发生变化
+func (r SyncResult) Changed() bool {
+	return len(r.Created)+len(r.Updated)+len(r.Removed) > 0
+}
+
+// Scope 返回便于阅读的作用域名称
+func (r SyncResult) Scope() string {
+	if r.GuildID == GlobalScope {
+		return "global"
+	}
+	return r.GuildID
+}
+
+// DesiredCommands 返回需要注册的命令，includeDev 为 false 时跳过开发命令
+func DesiredCommands(includeDev bool) []*discordgo.ApplicationCommand {
+	commands := make([]*discordgo.ApplicationCommand, 0, len(AllCommands))
+	for _, cmd := range AllCommands {
+		if !includeDev && DevCommands[cmd.Name] {
+			continue
+		}
+		commands = append(commands, cmd)
+	}
+	return commands
+}
+
+// syncScope 是一个需要同步的作用域及其应有的命令
+type syncScope struct {
+	guildID string
+	desired []*discordgo.ApplicationCommand
+}
+
+// configuredScopes 按配置列出需要同步的作用域：
+// commands.global 为 true 时注册为全局命令并清空各服务器中的命令，否则逐个服务器覆盖
+func configuredScopes() []syncScope {
+	desired := DesiredCommands(!config.Cfg.Commands.SkipDevCommands)
+
+	var scopes []syncScope
+	guildCommands := desired
+	if config.Cfg.Commands.Global {
+		scopes = append(scopes, syncScope{guildID: GlobalScope, desired: desired})
+		guildCommands = nil
+	}
+	for _, guildID := range config.Cfg.Commands.Allowguils {
+		scopes = append(scopes, syncScope{guildID: guildID, desired: guildCommands})
+	}
+	return scopes
+}
+
+// SyncConfigured 按配置逐个作用域同步命令
+func SyncConfigured(s *discordgo.Session) []SyncResult {
+	appID := s.State.User.ID
+	var results []SyncResult
+	for _, scope := range configuredScopes() {
+		results = append(results, Sync(s, appID, scope.guildID, scope.desired))
+	}
+	return results
+}
+
+// SyncConfiguredPaced 与 SyncConfigured 相同，但通过批量队列控制请求节奏；
+// ctx 被取消时尚未同步的作用域不会出现在结果中
+func SyncConfiguredPaced(ctx context.Context, s *discordgo.Session, queue *bulk.Queue) []SyncResult {
+	appID := s.State.User.ID
+	scopes := configuredScopes()
+	results := make([]SyncResult, len(scopes))
+	done := make([]bool, len(scopes))
+
+	tasks := make([]bulk.Task, 0, len(scopes))
+	for idx, scope := range scopes {
+		idx, scope := idx, scope
+		tasks = append(tasks, bulk.Task{
+			Name:  SyncResult{GuildID: scope.guildID}.Scope(),
+			Route: "commands:" + scope.guildID,
+			Run: func(ctx context.Context) error {
+				results[idx] = Sync(s, appID, scope.guildID, scope.desired)
+				done[idx] = true
+				return results[idx].Err
+			},
+		})
+	}
+	queue.Run(ctx, tasks)
+
+	synced := make([]SyncResult, 0, len(scopes))
+	for idx, result := range results {
+		if done[idx] {
+			synced = append(synced, result)
+		}
+	}
+	return synced
+}
+
+// Sync 将作用域内的命令批量覆盖为 desired，并与线上命令比较得出差异；没有差异时不发起覆盖请求
+func Sync(s *discordgo.Session, appID, guildID string, desired []*discordgo.ApplicationCommand) SyncResult {
+	result := SyncResult{GuildID: guildID}
+
+	live, err := s.ApplicationCommands(appID, guildID)
+	if err != nil {
+		result.Err = fmt.Errorf("获取线上命令失败: %w", err)
+		return result
+	}
+
+	liveByName := make(map[string]*discordgo.ApplicationCommand, len(live))
+	for _, cmd := range live {
+		liveByName[cmd.Name] = cmd
+	}
+
+	desiredNames := make(map[string]bool, len(desired))
+	for _, cmd := range desired {
+		desiredNames[cmd.Name] = true
+		existing, ok := liveByName[cmd.Name]
+		switch {
+		case !ok:
+			result.Created = append(result.Created, cmd.Name)
+		case !sameCommand(existing, cmd):
+			result.Updated = append(result.Updated, cmd.Name)
+		default:
+			result.Unchanged = append(result.Unchanged, cmd.Name)
+		}
+	}
+	for name := range liveByName {
+		if !desiredNames[name] {
+			result.Removed = append(result.Removed, name)
+		}
+	}
+	sort.Strings(result.Removed)
+
+	if !result.Changed() {
+		return result
+	}
+
+	if desired == nil {
+		desired = []*discordgo.ApplicationCommand{}
+	}
+	if _, err := s.ApplicationCommandBulkOverwrite(appID, guildID, desired); err != nil {
+		result.Err = fmt.Errorf("批量覆盖命令失败: %w", err)
+	}
+	return result
+}
+
+// commandShape 是比较命令时关心的字段，忽略 ID、版本等由 Discord 生成的字段
+type commandShape struct {
+	Type                     discordgo.ApplicationCommandType      `json:"type,omitempty"`
+	Description              string                                `json:"description,omitempty"`
+	DescriptionLocalizations *map[discordgo.Locale]string          `json:"description_localizations,omitempty"`
+	NameLocalizations        *map[discordgo.Locale]string          `json:"name_localizations,omitempty"`
+	DefaultMemberPermissions *int64                                `json:"default_member_permissions,omitempty"`
+	NSFW                     *bool                                 `json:"nsfw,omitempty"`
+	Options                  []*discordgo.ApplicationCommandOption `json:"options,omitempty"`
+}
+
+func sameCommand(live, desired *discordgo.ApplicationCommand) bool {
+	shape := func(cmd *discordgo.ApplicationCommand) string {
+		t := cmd.Type
+		if t == 0 {
+			t = discordgo.ChatApplicationCommand
+		}
+		var nsfw *bool
+		if cmd.NSFW != nil && *cmd.NSFW {
+			nsfw = cmd.NSFW
+		}
+		data, _ := json.Marshal(commandShape{
+			Type:                     t,
+			Description:              cmd.Description,
+			DescriptionLocalizations: nonEmptyLocalizations(cmd.DescriptionLocalizations),
+			NameLocalizations:        nonEmptyLocalizations(cmd.NameLocalizations),
+			DefaultMemberPermissions: cmd.DefaultMemberPermissions,
+			NSFW:                     nsfw,
+			Options:                  cmd.Options,
+		})
+		return string(data)
+	}
+	return shape(live) == shape(desired)
+}
+
+func nonEmptyLocalizations(m *map[discordgo.Locale]string) *map[discordgo.Locale]string {
+	if m == nil || len(*m) == 0 {
+		return nil
+	}
+	return m
+}
+
+// LogSyncResults 逐个作用域记录同步结果，全部成功时返回 true
+func LogSyncResults(results []SyncResult) bool {
+	ok := true
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			ok = false
+			log.Printf("同步命令失败 [%s]: %v", r.Scope(), r.Err)
+		case r.Changed():
+			log.Printf("已同步命令 [%s]: 新增 %v, 更新 %v, 移除 %v", r.Scope(), r.Created, r.Updated, r.Removed)
+		default:
+			log.Printf("命令无变化 [%s]", r.Scope())
+		}
+	}
+	return ok
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
@@ -71,3 +72,24 @@ func LoadConfig() (err error) {
 
 	return nil
 }
+
+// Validate 检查运行所必需的配置项
+func Validate() error {
+	var missing []string
+	if Cfg.Token == "" {
+		missing = append(missing, "token")
+	}
+	if len(Cfg.Commands.Allowguils) == 0 {
+		missing = append(missing, "commands.allowguils")
+	}
+	if Cfg.AmwayBot.Amway.ReviewChannelID == "" {
+		missing = append(missing, "amwayBot.amway.review_channel_id")
+	}
+	if Cfg.AmwayBot.Amway.PublishChannelID == "" {
+		missing = append(missing, "amwayBot.amway.publish_channel_id")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("缺少必需的配置项: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
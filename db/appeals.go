@@ -0,0 +1,72 @@
+package db
+
+import (
+	"amway/model"
+	"database/sql"
+	"time"
+)
+
+const (
+	// AppealStatusPending 表示申诉等待管理员处理
+	AppealStatusPending = "pending"
+	// AppealStatusUpheld 表示申诉被驳回，维持原结果
+	AppealStatusUpheld = "upheld"
+	// AppealStatusOverturned 表示申诉通过，原结果已撤销
+	AppealStatusOverturned = "overturned"
+)
+
+const appealColumns = `id, user_id, submission_id, kind, content, status, message_id, resolver_id, note, created_at, resolved_at`
+
+func scanAppeal(scanner rowScanner) (*model.Appeal, error) {
+	var a model.Appeal
+	err := scanner.Scan(&a.ID, &a.UserID, &a.SubmissionID, &a.Kind, &a.Content, &a.Status, &a.MessageID, &a.ResolverID, &a.Note, &a.CreatedAt, &a.ResolvedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+// CreateAppeal 添加一条待处理的申诉，返回其 ID
+func CreateAppeal(userID, submissionID, kind, content string) (int64, error) {
+	result, err := DB.Exec(`INSERT INTO appeals (user_id, submission_id, kind, content, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`, userID, submissionID, kind, content, AppealStatusPending, time.Now().Unix())
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// GetAppeal 按 ID 获取申诉，不存在时返回 nil
+func GetAppeal(id int64) (*model.Appeal, error) {
+	row := DB.QueryRow("SELECT "+appealColumns+" FROM appeals WHERE id = ?", id)
+	return scanAppeal(row)
+}
+
+// GetAppealBySubmission 获取用户对某条投稿某类结果的申诉，不存在时返回 nil
+func GetAppealBySubmission(submissionID, kind string) (*model.Appeal, error) {
+	row := DB.QueryRow("SELECT "+appealColumns+" FROM appeals WHERE submission_id = ? AND kind = ? ORDER BY id DESC LIMIT 1", submissionID, kind)
+	return scanAppeal(row)
+}
+
+// UpdateAppealMessageID 记录申诉在申诉频道中的消息 ID
+func UpdateAppealMessageID(id int64, messageID string) error {
+	_, err := DB.Exec("UPDATE appeals SET message_id = ? WHERE id = ?", messageID, id)
+	return err
+}
+
+// ResolveAppealInTx 在事务中结案一条待处理的申诉；申诉已被处理时返回 false
+func ResolveAppealInTx(tx *sql.Tx, id int64, status, resolverID, note string) (bool, error) {
+	result, err := tx.Exec(`UPDATE appeals SET status = ?, resolver_id = ?, note = ?, resolved_at = ?
+		WHERE id = ? AND status = ?`, status, resolverID, note, time.Now().Unix(), id, AppealStatusPending)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -1,6 +1,8 @@
 package db
 
 import (
+	"database/sql"
+	"fmt"
 	"log"
 )
 
@@ -30,7 +32,10 @@ func createTables() {
 		is_deleted INTEGER NOT NULL DEFAULT 0,
 		is_anonymous INTEGER NOT NULL DEFAULT 0,
 		vote_file_id TEXT,
-		thread_message_id TEXT NOT NULL DEFAULT '0'
+		thread_message_id TEXT NOT NULL DEFAULT '0',
+		review_message_id TEXT NOT NULL DEFAULT '',
+		reopened_at INTEGER NOT NULL DEFAULT 0,
+		reply_to_original INTEGER NOT NULL DEFAULT 0
 	);`
 
 	_, err := DB.Exec(createRecommendationsTableSQL)
@@ -38,12 +43,23 @@ func createTables() {
 		log.Fatalf("Failed to create recommendations table: %v", err)
 	}
 
+	// 旧版数据库的 recommendations 表缺少后来新增的列，逐一补齐
+	ensureColumns("recommendations", []columnDef{
+		{"review_message_id", "TEXT NOT NULL DEFAULT ''"},
+		{"reopened_at", "INTEGER NOT NULL DEFAULT 0"},
+		{"reply_to_original", "INTEGER NOT NULL DEFAULT 0"},
+	})
+
 	// 用于创建 'users' 表的 SQL 语句
 	createUsersTableSQL := `
 	CREATE TABLE IF NOT EXISTS users (
 		user_id TEXT PRIMARY KEY,
 		featured_count INTEGER NOT NULL DEFAULT 0,
-		rejected_count INTEGER NOT NULL DEFAULT 0
+		rejected_count INTEGER NOT NULL DEFAULT 0,
+		ban_count INTEGER NOT NULL DEFAULT 0,
+		is_permanently_banned INTEGER NOT NULL DEFAULT 0,
+		banned_until INTEGER,
+		notify_opt_out INTEGER NOT NULL DEFAULT 0
 	);`
 
 	_, err = DB.Exec(createUsersTableSQL)
@@ -51,6 +67,14 @@ func createTables() {
 		log.Fatalf("Failed to create users table: %v", err)
 	}
 
+	// 旧版数据库的 users 表缺少后来新增的列，逐一补齐
+	ensureColumns("users", []columnDef{
+		{"ban_count", "INTEGER NOT NULL DEFAULT 0"},
+		{"is_permanently_banned", "INTEGER NOT NULL DEFAULT 0"},
+		{"banned_until", "INTEGER"},
+		{"notify_opt_out", "INTEGER NOT NULL DEFAULT 0"},
+	})
+
 	// 用于顺序 ID 生成的 'id_counter' 表的 SQL 语句
 	createIdCounterTableSQL := `
 	CREATE TABLE IF NOT EXISTS id_counter (
@@ -79,5 +103,206 @@ func createTables() {
 		log.Fatalf("Failed to create submission_reactions table: %v", err)
 	}
 
+	// 用于创建 'outbox' 表的 SQL 语句，保存待执行的 Discord 副作用
+	createOutboxTableSQL := `
+	CREATE TABLE IF NOT EXISTS outbox (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		action TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		attempts INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		next_attempt_at INTEGER NOT NULL,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_outbox_status_next ON outbox (status, next_attempt_at);`
+
+	_, err = DB.Exec(createOutboxTableSQL)
+	if err != nil {
+		log.Fatalf("Failed to create outbox table: %v", err)
+	}
+
+	// 用于创建 'notifications' 表的 SQL 语句，记录通知的投递状态
+	createNotificationsTableSQL := `
+	CREATE TABLE IF NOT EXISTS notifications (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id TEXT NOT NULL,
+		submission_id TEXT NOT NULL DEFAULT '',
+		kind TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		sent_count INTEGER NOT NULL DEFAULT 0,
+		status TEXT NOT NULL DEFAULT 'pending',
+		channel TEXT NOT NULL DEFAULT '',
+		last_error TEXT NOT NULL DEFAULT '',
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications (user_id, created_at);`
+
+	_, err = DB.Exec(createNotificationsTableSQL)
+	if err != nil {
+		log.Fatalf("Failed to create notifications table: %v", err)
+	}
+	ensureColumns("notifications", []columnDef{
+		{"sent_count", "INTEGER NOT NULL DEFAULT 0"},
+	})
+
+	// 用于创建 'appeals' 表的 SQL 语句，记录用户对审核结果的申诉
+	createAppealsTableSQL := `
+	CREATE TABLE IF NOT EXISTS appeals (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id TEXT NOT NULL,
+		submission_id TEXT NOT NULL,
+		kind TEXT NOT NULL,
+		content TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		message_id TEXT NOT NULL DEFAULT '',
+		resolver_id TEXT NOT NULL DEFAULT '',
+		note TEXT NOT NULL DEFAULT '',
+		created_at INTEGER NOT NULL,
+		resolved_at INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE INDEX IF NOT EXISTS idx_appeals_submission ON appeals (submission_id, kind);`
+
+	_, err = DB.Exec(createAppealsTableSQL)
+	if err != nil {
+		log.Fatalf("Failed to create appeals table: %v", err)
+	}
+
+	// 用于创建 'reason_templates' 表的 SQL 语句，记录各服务器预设的不通过/封禁理由
+	createReasonTemplatesTableSQL := `
+	CREATE TABLE IF NOT EXISTS reason_templates (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		guild_id TEXT NOT NULL,
+		kind TEXT NOT NULL,
+		label TEXT NOT NULL,
+		text TEXT NOT NULL,
+		usage_count INTEGER NOT NULL DEFAULT 0,
+		created_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_reason_templates_guild ON reason_templates (guild_id, kind);`
+
+	_, err = DB.Exec(createReasonTemplatesTableSQL)
+	if err != nil {
+		log.Fatalf("Failed to create reason_templates table: %v", err)
+	}
+
+	// 用于创建 'review_assignments' 表的 SQL 语句，记录投稿被指派给哪些审核员
+	createReviewAssignmentsTableSQL := `
+	CREATE TABLE IF NOT EXISTS review_assignments (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		submission_id TEXT NOT NULL,
+		reviewer_id TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'active',
+		assigned_at INTEGER NOT NULL,
+		closed_at INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE INDEX IF NOT EXISTS idx_review_assignments_submission ON review_assignments (submission_id);
+	CREATE INDEX IF NOT EXISTS idx_review_assignments_status ON review_assignments (status, assigned_at);`
+
+	_, err = DB.Exec(createReviewAssignmentsTableSQL)
+	if err != nil {
+		log.Fatalf("Failed to create review_assignments table: %v", err)
+	}
+
+	// 用于创建 'reviewer_away' 表的 SQL 语句，记录暂停接受指派的审核员
+	createReviewerAwayTableSQL := `
+	CREATE TABLE IF NOT EXISTS reviewer_away (
+		user_id TEXT PRIMARY KEY,
+		away_until INTEGER NOT NULL DEFAULT 0,
+		created_at INTEGER NOT NULL
+	);`
+
+	_, err = DB.Exec(createReviewerAwayTableSQL)
+	if err != nil {
+		log.Fatalf("Failed to create reviewer_away table: %v", err)
+	}
+
+	// 用于创建 'reviewer_notes' 表的 SQL 语句，记录审核员对用户的备注，投稿人不可见
+	createReviewerNotesTableSQL := `
+	CREATE TABLE IF NOT EXISTS reviewer_notes (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id TEXT NOT NULL,
+		submission_id TEXT NOT NULL DEFAULT '',
+		author_id TEXT NOT NULL,
+		content TEXT NOT NULL,
+		created_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_reviewer_notes_user ON reviewer_notes (user_id);`
+
+	_, err = DB.Exec(createReviewerNotesTableSQL)
+	if err != nil {
+		log.Fatalf("Failed to create reviewer_notes table: %v", err)
+	}
+
+	// 用于创建 'warnings' 表的 SQL 语句，记录用户收到的警告及其触发的处罚
+	createWarningsTableSQL := `
+	CREATE TABLE IF NOT EXISTS warnings (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id TEXT NOT NULL,
+		guild_id TEXT NOT NULL DEFAULT '',
+		submission_id TEXT NOT NULL DEFAULT '',
+		reason TEXT NOT NULL,
+		issuer_id TEXT NOT NULL DEFAULT '',
+		escalation TEXT NOT NULL DEFAULT '',
+		escalation_kind TEXT NOT NULL DEFAULT '',
+		banned_until INTEGER NOT NULL DEFAULT 0,
+		created_at INTEGER NOT NULL,
+		expires_at INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE INDEX IF NOT EXISTS idx_warnings_user ON warnings (user_id, expires_at);`
+
+	_, err = DB.Exec(createWarningsTableSQL)
+	if err != nil {
+		log.Fatalf("Failed to create warnings table: %v", err)
+	}
+	ensureColumns("warnings", []columnDef{
+		{"escalation_kind", "TEXT NOT NULL DEFAULT ''"},
+		{"banned_until", "INTEGER NOT NULL DEFAULT 0"},
+	})
+
 	log.Println("Database tables initialized successfully.")
 }
+
+// columnDef 描述一个需要补齐的列
+type columnDef struct {
+	name       string
+	definition string
+}
+
+// ensureColumns 为已存在的表补齐缺失的列
+func ensureColumns(table string, columns []columnDef) {
+	rows, err := DB.Query("PRAGMA table_info(" + table + ")")
+	if err != nil {
+		log.Fatalf("Failed to inspect %s table: %v", table, err)
+	}
+
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			colType    string
+			notNull    int
+			defaultVal sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultVal, &pk); err != nil {
+			rows.Close()
+			log.Fatalf("Failed to inspect %s table: %v", table, err)
+		}
+		existing[name] = true
+	}
+	rows.Close()
+
+	for _, col := range columns {
+		if existing[col.name] {
+			continue
+		}
+		if _, err := DB.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, col.name, col.definition)); err != nil {
+			log.Fatalf("Failed to add column %s to %s table: %v", col.name, table, err)
+		}
+		log.Printf("Added column %s to %s table", col.name, table)
+	}
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"amway/model"
+	"database/sql"
+	"time"
+)
+
+const (
+	// NotificationStatusPending 表示等待投递
+	NotificationStatusPending = "pending"
+	// NotificationStatusSent 表示已通过私信送达
+	NotificationStatusSent = "sent"
+	// NotificationStatusFallback 表示私信失败，已在公告频道提及用户
+	NotificationStatusFallback = "fallback"
+	// NotificationStatusFailed 表示私信与回退均失败
+	NotificationStatusFailed = "failed"
+)
+
+const notificationColumns = `id, user_id, submission_id, kind, payload, sent_count, status, channel, last_error, created_at, updated_at`
+
+func scanNotification(scanner rowScanner) (*model.Notification, error) {
+	var n model.Notification
+	err := scanner.Scan(&n.ID, &n.UserID, &n.SubmissionID, &n.Kind, &n.Payload, &n.SentCount, &n.Status, &n.Channel, &n.LastError, &n.CreatedAt, &n.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &n, nil
+}
+
+// CreateNotificationInTx 在事务中添加一条待投递的通知，返回其 ID
+func CreateNotificationInTx(tx *sql.Tx, userID, submissionID, kind, payload string) (int64, error) {
+	now := time.Now().Unix()
+	result, err := tx.Exec(`INSERT INTO notifications (user_id, submission_id, kind, payload, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`, userID, submissionID, kind, payload, NotificationStatusPending, now, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// GetNotification 按 ID 获取通知，不存在时返回 nil
+func GetNotification(id int64) (*model.Notification, error) {
+	row := DB.QueryRow("SELECT "+notificationColumns+" FROM notifications WHERE id = ?", id)
+	return scanNotification(row)
+}
+
+// SetNotificationSentCount 记录已经送达的消息条数
+func SetNotificationSentCount(id int64, count int) error {
+	_, err := DB.Exec("UPDATE notifications SET sent_count = ?, updated_at = ? WHERE id = ?", count, time.Now().Unix(), id)
+	return err
+}
+
+// UpdateNotificationStatus 更新通知的投递状态、实际使用的渠道和错误信息
+func UpdateNotificationStatus(id int64, status, channel, lastError string) error {
+	_, err := DB.Exec("UPDATE notifications SET status = ?, channel = ?, last_error = ?, updated_at = ? WHERE id = ?",
+		status, channel, lastError, time.Now().Unix(), id)
+	return err
+}
+
+// GetUserNotifications 获取用户最近的通知
+func GetUserNotifications(userID string, limit int) ([]*model.Notification, error) {
+	rows, err := DB.Query("SELECT "+notificationColumns+" FROM notifications WHERE user_id = ? ORDER BY created_at DESC, id DESC LIMIT ?", userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []*model.Notification
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		if n != nil {
+			notifications = append(notifications, n)
+		}
+	}
+	return notifications, rows.Err()
+}
+
+// HasNotification 判断投稿在当前审核周期内是否已经生成过指定类型的通知
+// 投稿被重新打开后，之前的通知不再计入
+func HasNotification(submissionID, kind string) (bool, error) {
+	var count int
+	err := DB.QueryRow(`SELECT COUNT(*) FROM notifications WHERE submission_id = ? AND kind = ?
+		AND created_at >= COALESCE((SELECT reopened_at FROM recommendations WHERE id = ?), 0)`, submissionID, kind, submissionID).Scan(&count)
+	return count > 0, err
+}
@@ -0,0 +1,164 @@
+package db
+
+import (
+	"amway/model"
+	"database/sql"
+	"time"
+)
+
+const (
+	// OutboxStatusPending 表示等待执行或等待重试
+	OutboxStatusPending = "pending"
+	// OutboxStatusDone 表示已成功执行
+	OutboxStatusDone = "done"
+	// OutboxStatusDead 表示超过重试次数或遇到不可恢复的错误
+	OutboxStatusDead = "dead"
+)
+
+const outboxColumns = `id, action, payload, status, attempts, last_error, next_attempt_at, created_at, updated_at`
+
+func scanOutboxItem(scanner rowScanner) (*model.OutboxItem, error) {
+	var item model.OutboxItem
+	err := scanner.Scan(&item.ID, &item.Action, &item.Payload, &item.Status, &item.Attempts, &item.LastError, &item.NextAttemptAt, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+func queryOutboxItems(query string, args ...interface{}) ([]*model.OutboxItem, error) {
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*model.OutboxItem
+	for rows.Next() {
+		item, err := scanOutboxItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		if item != nil {
+			items = append(items, item)
+		}
+	}
+	return items, rows.Err()
+}
+
+// EnqueueOutbox 向 outbox 表中添加一条待执行的动作
+func EnqueueOutbox(action, payload string) (int64, error) {
+	tx, err := DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	id, err := EnqueueOutboxInTx(tx, action, payload)
+	if err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+// EnqueueOutboxInTx 在事务中向 outbox 表中添加一条待执行的动作
+func EnqueueOutboxInTx(tx *sql.Tx, action, payload string) (int64, error) {
+	now := time.Now().Unix()
+	result, err := tx.Exec(`INSERT INTO outbox (action, payload, status, next_attempt_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)`, action, payload, OutboxStatusPending, now, now, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// GetDueOutboxItems 按创建顺序获取已到执行时间的待处理动作
+func GetDueOutboxItems(limit int) ([]*model.OutboxItem, error) {
+	return queryOutboxItems(`SELECT `+outboxColumns+` FROM outbox
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY id ASC LIMIT ?`, OutboxStatusPending, time.Now().Unix(), limit)
+}
+
+// GetStuckOutboxItems 获取已进入死信或至少失败过一次的动作
+func GetStuckOutboxItems(limit int) ([]*model.OutboxItem, error) {
+	return queryOutboxItems(`SELECT `+outboxColumns+` FROM outbox
+		WHERE status = ? OR (status = ? AND attempts > 0)
+		ORDER BY id DESC LIMIT ?`, OutboxStatusDead, OutboxStatusPending, limit)
+}
+
+// GetOutboxItem 按 ID 获取单条动作
+func GetOutboxItem(id int64) (*model.OutboxItem, error) {
+	row := DB.QueryRow(`SELECT `+outboxColumns+` FROM outbox WHERE id = ?`, id)
+	return scanOutboxItem(row)
+}
+
+// MarkOutboxDone 将动作标记为已完成
+func MarkOutboxDone(id int64) error {
+	_, err := DB.Exec("UPDATE outbox SET status = ?, attempts = attempts + 1, last_error = '', updated_at = ? WHERE id = ?",
+		OutboxStatusDone, time.Now().Unix(), id)
+	return err
+}
+
+// MarkOutboxRetry 记录一次失败并安排下一次重试
+func MarkOutboxRetry(id int64, lastError string, nextAttemptAt time.Time) error {
+	_, err := DB.Exec("UPDATE outbox SET attempts = attempts + 1, last_error = ?, next_attempt_at = ?, updated_at = ? WHERE id = ?",
+		lastError, nextAttemptAt.Unix(), time.Now().Unix(), id)
+	return err
+}
+
+// MarkOutboxDead 将动作移入死信
+func MarkOutboxDead(id int64, lastError string) error {
+	_, err := DB.Exec("UPDATE outbox SET status = ?, attempts = attempts + 1, last_error = ?, updated_at = ? WHERE id = ?",
+		OutboxStatusDead, lastError, time.Now().Unix(), id)
+	return err
+}
+
+// RequeueOutboxItem 将死信或失败的动作重置为立即重试
+func RequeueOutboxItem(id int64) error {
+	now := time.Now().Unix()
+	result, err := DB.Exec("UPDATE outbox SET status = ?, attempts = 0, next_attempt_at = ?, updated_at = ? WHERE id = ? AND status != ?",
+		OutboxStatusPending, now, now, id, OutboxStatusDone)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// CountOutboxByStatus 统计各状态的动作数量
+func CountOutboxByStatus() (map[string]int, error) {
+	rows, err := DB.Query("SELECT status, COUNT(*) FROM outbox GROUP BY status")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+	return counts, rows.Err()
+}
+
+// PurgeDoneOutboxItems 删除早于指定时间的已完成动作
+func PurgeDoneOutboxItems(before time.Time) (int64, error) {
+	result, err := DB.Exec("DELETE FROM outbox WHERE status = ? AND updated_at < ?", OutboxStatusDone, before.Unix())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"amway/model"
+	"database/sql"
+	"time"
+)
+
+const (
+	// ReasonKindReject 表示不通过理由模板
+	ReasonKindReject = "reject"
+	// ReasonKindBan 表示封禁理由模板
+	ReasonKindBan = "ban"
+)
+
+const reasonTemplateColumns = `id, guild_id, kind, label, text, usage_count, created_at`
+
+func scanReasonTemplate(scanner rowScanner) (*model.ReasonTemplate, error) {
+	var t model.ReasonTemplate
+	err := scanner.Scan(&t.ID, &t.GuildID, &t.Kind, &t.Label, &t.Text, &t.UsageCount, &t.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// CreateReasonTemplate 为服务器添加一条理由模板，返回其 ID
+func CreateReasonTemplate(guildID, kind, label, text string) (int64, error) {
+	result, err := DB.Exec(`INSERT INTO reason_templates (guild_id, kind, label, text, created_at)
+		VALUES (?, ?, ?, ?, ?)`, guildID, kind, label, text, time.Now().Unix())
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// GetReasonTemplate 按 ID 获取理由模板，不存在时返回 nil
+func GetReasonTemplate(id int64) (*model.ReasonTemplate, error) {
+	row := DB.QueryRow("SELECT "+reasonTemplateColumns+" FROM reason_templates WHERE id = ?", id)
+	return scanReasonTemplate(row)
+}
+
+// GetReasonTemplates 获取服务器的全部理由模板，按类型和添加顺序排列
+func GetReasonTemplates(guildID string) ([]*model.ReasonTemplate, error) {
+	rows, err := DB.Query("SELECT "+reasonTemplateColumns+" FROM reason_templates WHERE guild_id = ? ORDER BY kind DESC, id", guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []*model.ReasonTemplate
+	for rows.Next() {
+		t, err := scanReasonTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+// DeleteReasonTemplate 删除服务器的一条理由模板，返回是否删除成功
+func DeleteReasonTemplate(guildID string, id int64) (bool, error) {
+	result, err := DB.Exec("DELETE FROM reason_templates WHERE id = ? AND guild_id = ?", id, guildID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// IncrementReasonTemplateUsage 记录理由模板被使用一次
+func IncrementReasonTemplateUsage(id int64) error {
+	_, err := DB.Exec("UPDATE reason_templates SET usage_count = usage_count + 1 WHERE id = ?", id)
+	return err
+}
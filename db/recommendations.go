@@ -22,6 +22,7 @@ func scanSubmission(scanner rowScanner) (*model.Submission, error) {
 		&sub.GuildID, &sub.OriginalTitle, &sub.OriginalAuthor,
 		&sub.RecommendTitle, &sub.RecommendContent, &sub.OriginalPostTimestamp, &sub.FinalAmwayMessageID,
 		&sub.Upvotes, &sub.Questions, &sub.Downvotes, &sub.IsAnonymous, &sub.Status, &sub.VoteFileID, &sub.ThreadMessageID,
+		&sub.ReviewMessageID, &sub.ReplyToOriginal,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -34,11 +35,11 @@ func scanSubmission(scanner rowScanner) (*model.Submission, error) {
 
 // AddSubmission 将新投稿添加到 recommendations 表中（旧版）
 func AddSubmission(userID, url, title, content, guildID, authorNickname string) (string, error) {
-	return AddSubmissionV2(userID, url, title, content, "", "", "", guildID, authorNickname, false)
+	return AddSubmissionV2(userID, url, title, content, "", "", "", guildID, authorNickname, false, false)
 }
 
 // AddSubmissionV2 使用原始帖子信息和推荐内容添加新投稿
-func AddSubmissionV2(userID, url, recommendTitle, recommendContent, originalTitle, originalAuthor string, originalPostTimestamp string, guildID string, authorNickname string, isAnonymous bool) (string, error) {
+func AddSubmissionV2(userID, url, recommendTitle, recommendContent, originalTitle, originalAuthor string, originalPostTimestamp string, guildID string, authorNickname string, isAnonymous bool, replyToOriginal bool) (string, error) {
 	tx, err := DB.Begin()
 	if err != nil {
 		return "", err
@@ -61,8 +62,9 @@ func AddSubmissionV2(userID, url, recommendTitle, recommendContent, originalTitl
 
 	stmt, err := tx.Prepare(`INSERT INTO recommendations(
 		id, author_id, author_nickname, content, post_url, created_at, guild_id,
-		original_title, original_author, recommend_title, recommend_content, original_post_timestamp, is_anonymous, vote_file_id
-	) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+		original_title, original_author, recommend_title, recommend_content, original_post_timestamp, is_anonymous, vote_file_id,
+		reply_to_original
+	) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
 	if err != nil {
 		return "", err
 	}
@@ -76,6 +78,7 @@ func AddSubmissionV2(userID, url, recommendTitle, recommendContent, originalTitl
 	_, err = stmt.Exec(
 		submissionID, userID, authorNickname, fullContent, url, time.Now().Unix(), guildID,
 		originalTitle, originalAuthor, recommendTitle, recommendContent, originalPostTimestamp, isAnonymous, voteFileID,
+		replyToOriginal,
 	)
 	if err != nil {
 		return "", err
@@ -95,6 +98,56 @@ func UpdateSubmissionReviewer(submissionID, status, reviewerID string) error {
 	return err
 }
 
+// UpdateSubmissionReviewerInTx 在事务中更新投稿的状态和审核员
+func UpdateSubmissionReviewerInTx(tx *sql.Tx, submissionID, status, reviewerID string) error {
+	_, err := tx.Exec("UPDATE recommendations SET status = ?, reviewer_id = ? WHERE id = ?", status, reviewerID, submissionID)
+	return err
+}
+
+// ApproveRejectedSubmissionInTx 在事务中将仍处于 rejected 状态的投稿改为 approved，返回是否更新成功
+func ApproveRejectedSubmissionInTx(tx *sql.Tx, submissionID, reviewerID string) (bool, error) {
+	result, err := tx.Exec("UPDATE recommendations SET status = 'approved', reviewer_id = ? WHERE id = ? AND status = 'rejected' AND is_deleted = 0",
+		reviewerID, submissionID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// ReopenSubmissionInTx 在事务中将已结束审核的投稿退回待审核，并清除发布消息和原帖通知的记录
+// 仅当投稿仍处于 approved/featured/rejected 状态时生效，返回是否更新成功
+func ReopenSubmissionInTx(tx *sql.Tx, submissionID string) (bool, error) {
+	result, err := tx.Exec(`UPDATE recommendations
+		SET status = 'pending', reviewer_id = '', final_amway_message_id = '', thread_message_id = '0', reopened_at = ?
+		WHERE id = ? AND status IN ('approved', 'featured', 'rejected') AND is_deleted = 0`, time.Now().Unix(), submissionID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// CountSubmissionsByStatus 按状态统计未删除的投稿数量
+func CountSubmissionsByStatus() (map[string]int, error) {
+	rows, err := DB.Query("SELECT status, COUNT(*) FROM recommendations WHERE is_deleted = 0 GROUP BY status")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+	return counts, rows.Err()
+}
+
 // DeleteSubmission 从 recommendations 表中删除一个投稿
 func DeleteSubmission(submissionID string) error {
 	_, err := DB.Exec("DELETE FROM recommendations WHERE id = ?", submissionID)
@@ -113,7 +166,9 @@ func GetSubmission(submissionID string) (*model.Submission, error) {
 		COALESCE(original_post_timestamp, '') as original_post_timestamp,
 		COALESCE(final_amway_message_id, '') as final_amway_message_id,
 		upvotes, questions, downvotes, is_anonymous, status, COALESCE(vote_file_id, '') as vote_file_id,
-		COALESCE(thread_message_id, '0') as thread_message_id
+		COALESCE(thread_message_id, '0') as thread_message_id,
+		COALESCE(review_message_id, '') as review_message_id,
+		reply_to_original
 	FROM recommendations WHERE id = ? AND is_deleted = 0`, submissionID)
 
 	return scanSubmission(row)
@@ -131,6 +186,105 @@ func UpdateThreadMessageID(submissionID, messageID string) error {
 	return err
 }
 
+// ResubmitRevision 用修改后的内容更新一条待修改的投稿，并将其重新置为待审核；投稿不处于待修改状态时返回 false
+func ResubmitRevision(submissionID, userID, recommendTitle, recommendContent string) (bool, error) {
+	result, err := DB.Exec(`UPDATE recommendations
+		SET recommend_title = ?, recommend_content = ?,
+			content = CASE WHEN COALESCE(original_title, '') = '' AND COALESCE(original_author, '') = '' THEN ? ELSE ? END,
+			status = 'pending'
+		WHERE id = ? AND author_id = ? AND status = 'revision' AND is_deleted = 0`,
+		recommendTitle, recommendContent,
+		fmt.Sprintf("**%s**\n\n%s", recommendTitle, recommendContent), recommendContent,
+		submissionID, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// UpdateReviewMessageID 更新投稿在审核频道中的消息 ID
+func UpdateReviewMessageID(submissionID, messageID string) error {
+	_, err := DB.Exec("UPDATE recommendations SET review_message_id = ? WHERE id = ?", messageID, submissionID)
+	return err
+}
+
+// GetPendingSubmissions 按投稿时间从早到晚获取所有待审核的投稿（不包括已删除的）
+func GetPendingSubmissions() ([]*model.Submission, error) {
+	rows, err := DB.Query(`SELECT
+		id, author_id, COALESCE(author_nickname, '') as author_nickname, content, post_url, created_at,
+		COALESCE(guild_id, '') as guild_id,
+		COALESCE(original_title, '') as original_title,
+		COALESCE(original_author, '') as original_author,
+		COALESCE(recommend_title, '') as recommend_title,
+		COALESCE(recommend_content, '') as recommend_content,
+		COALESCE(original_post_timestamp, '') as original_post_timestamp,
+		COALESCE(final_amway_message_id, '') as final_amway_message_id,
+		upvotes, questions, downvotes, is_anonymous, status, COALESCE(vote_file_id, '') as vote_file_id,
+		COALESCE(thread_message_id, '0') as thread_message_id,
+		COALESCE(review_message_id, '') as review_message_id,
+		reply_to_original
+	FROM recommendations
+	WHERE status = 'pending' AND is_deleted = 0
+	ORDER BY created_at ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var submissions []*model.Submission
+	for rows.Next() {
+		submission, err := scanSubmission(rows)
+		if err != nil {
+			return nil, err
+		}
+		if submission != nil {
+			submissions = append(submissions, submission)
+		}
+	}
+	return submissions, rows.Err()
+}
+
+// GetVotedSubmissions 获取服务器中有投票记录的投稿（包括已删除的），用于审核统计
+// 较早提交的投稿也可能在统计范围内收到投票，因此不按提交时间筛选，由调用方按投票时间筛选
+func GetVotedSubmissions(guildID string) ([]*model.Submission, error) {
+	rows, err := DB.Query(`SELECT
+		id, author_id, COALESCE(author_nickname, '') as author_nickname, content, post_url, created_at,
+		COALESCE(guild_id, '') as guild_id,
+		COALESCE(original_title, '') as original_title,
+		COALESCE(original_author, '') as original_author,
+		COALESCE(recommend_title, '') as recommend_title,
+		COALESCE(recommend_content, '') as recommend_content,
+		COALESCE(original_post_timestamp, '') as original_post_timestamp,
+		COALESCE(final_amway_message_id, '') as final_amway_message_id,
+		upvotes, questions, downvotes, is_anonymous, status, COALESCE(vote_file_id, '') as vote_file_id,
+		COALESCE(thread_message_id, '0') as thread_message_id,
+		COALESCE(review_message_id, '') as review_message_id,
+		reply_to_original
+	FROM recommendations
+	WHERE guild_id = ? AND vote_file_id IS NOT NULL AND vote_file_id != ''
+	ORDER BY created_at ASC`, guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var submissions []*model.Submission
+	for rows.Next() {
+		submission, err := scanSubmission(rows)
+		if err != nil {
+			return nil, err
+		}
+		if submission != nil {
+			submissions = append(submissions, submission)
+		}
+	}
+	return submissions, rows.Err()
+}
+
 // GetSubmissionByMessageID 按最终消息 ID 检索投稿（不包括已删除的）
 func GetSubmissionByMessageID(messageID string) (*model.Submission, error) {
 	row := DB.QueryRow(`SELECT
@@ -143,7 +297,9 @@ func GetSubmissionByMessageID(messageID string) (*model.Submission, error) {
 		COALESCE(original_post_timestamp, '') as original_post_timestamp,
 		COALESCE(final_amway_message_id, '') as final_amway_message_id,
 		upvotes, questions, downvotes, is_anonymous, status, COALESCE(vote_file_id, '') as vote_file_id,
-		COALESCE(thread_message_id, '0') as thread_message_id
+		COALESCE(thread_message_id, '0') as thread_message_id,
+		COALESCE(review_message_id, '') as review_message_id,
+		reply_to_original
 	FROM recommendations WHERE final_amway_message_id = ? AND is_deleted = 0`, messageID)
 
 	return scanSubmission(row)
@@ -201,7 +357,9 @@ func GetSubmissionWithDeleted(submissionID string) (*model.Submission, error) {
 		COALESCE(original_post_timestamp, '') as original_post_timestamp,
 		COALESCE(final_amway_message_id, '') as final_amway_message_id,
 		upvotes, questions, downvotes, is_anonymous, status, COALESCE(vote_file_id, '') as vote_file_id,
-		COALESCE(thread_message_id, '0') as thread_message_id
+		COALESCE(thread_message_id, '0') as thread_message_id,
+		COALESCE(review_message_id, '') as review_message_id,
+		reply_to_original
 	FROM recommendations WHERE id = ?`, submissionID)
 
 	return scanSubmission(row)
@@ -232,7 +390,9 @@ func GetSubmissionsByAuthor(authorID string, guildID string) ([]*model.Submissio
 		COALESCE(original_post_timestamp, '') as original_post_timestamp,
 		COALESCE(final_amway_message_id, '') as final_amway_message_id,
 		upvotes, questions, downvotes, is_anonymous, status, COALESCE(vote_file_id, '') as vote_file_id,
-		COALESCE(thread_message_id, '0') as thread_message_id
+		COALESCE(thread_message_id, '0') as thread_message_id,
+		COALESCE(review_message_id, '') as review_message_id,
+		reply_to_original
 	FROM recommendations WHERE author_id = ? AND guild_id = ? AND is_deleted = 0 ORDER BY created_at DESC`
 
 	rows, err := DB.Query(query, authorID, guildID)
@@ -271,7 +431,9 @@ func GetAllSubmissionsByAuthor(authorID string) ([]*model.Submission, error) {
 		COALESCE(original_post_timestamp, '') as original_post_timestamp,
 		COALESCE(final_amway_message_id, '') as final_amway_message_id,
 		upvotes, questions, downvotes, is_anonymous, status, COALESCE(vote_file_id, '') as vote_file_id,
-		COALESCE(thread_message_id, '0') as thread_message_id
+		COALESCE(thread_message_id, '0') as thread_message_id,
+		COALESCE(review_message_id, '') as review_message_id,
+		reply_to_original
 	FROM recommendations WHERE author_id = ? AND is_deleted = 0 ORDER BY created_at DESC`
 
 	rows, err := DB.Query(query, authorID)
@@ -324,7 +486,9 @@ func MyAmwayGetUserSubmissions(authorID string, page int, pageSize int) ([]*mode
 		COALESCE(original_post_timestamp, '') as original_post_timestamp,
 		COALESCE(final_amway_message_id, '') as final_amway_message_id,
 		upvotes, questions, downvotes, is_anonymous, status, COALESCE(vote_file_id, '') as vote_file_id,
-		COALESCE(thread_message_id, '0') as thread_message_id
+		COALESCE(thread_message_id, '0') as thread_message_id,
+		COALESCE(review_message_id, '') as review_message_id,
+		reply_to_original
 	FROM recommendations WHERE author_id = ? ORDER BY created_at DESC LIMIT ? OFFSET ?`
 
 	rows, err := DB.Query(query, authorID, pageSize, offset)
@@ -432,7 +596,9 @@ func GetPendingSubmissionsWithoutMessage() ([]*model.Submission, error) {
 		COALESCE(original_post_timestamp, '') as original_post_timestamp,
 		COALESCE(final_amway_message_id, '') as final_amway_message_id,
 		upvotes, questions, downvotes, is_anonymous, status, COALESCE(vote_file_id, '') as vote_file_id,
-		COALESCE(thread_message_id, '0') as thread_message_id
+		COALESCE(thread_message_id, '0') as thread_message_id,
+		COALESCE(review_message_id, '') as review_message_id,
+		reply_to_original
 	FROM recommendations
 	WHERE status = 'pending'
 		AND (final_amway_message_id IS NULL OR final_amway_message_id = '')
@@ -0,0 +1,148 @@
+package db
+
+import (
+	"amway/model"
+	"database/sql"
+	"time"
+)
+
+const (
+	// AssignmentActive 表示被指派的审核员尚未投票
+	AssignmentActive = "active"
+	// AssignmentDone 表示审核员已投票或投稿已审核结束
+	AssignmentDone = "done"
+	// AssignmentExpired 表示指派超时，已转派给其他审核员
+	AssignmentExpired = "expired"
+)
+
+const reviewAssignmentColumns = `id, submission_id, reviewer_id, status, assigned_at, closed_at`
+
+func scanReviewAssignment(scanner rowScanner) (*model.ReviewAssignment, error) {
+	var a model.ReviewAssignment
+	err := scanner.Scan(&a.ID, &a.SubmissionID, &a.ReviewerID, &a.Status, &a.AssignedAt, &a.ClosedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func queryReviewAssignments(query string, args ...interface{}) ([]*model.ReviewAssignment, error) {
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assignments []*model.ReviewAssignment
+	for rows.Next() {
+		a, err := scanReviewAssignment(rows)
+		if err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, a)
+	}
+	return assignments, rows.Err()
+}
+
+// CreateReviewAssignment 把投稿指派给一名审核员
+func CreateReviewAssignment(submissionID, reviewerID string) error {
+	_, err := DB.Exec("INSERT INTO review_assignments (submission_id, reviewer_id, status, assigned_at) VALUES (?, ?, ?, ?)",
+		submissionID, reviewerID, AssignmentActive, time.Now().Unix())
+	return err
+}
+
+// GetSubmissionAssignments 获取投稿的全部指派记录（含已结束的），按指派顺序排列
+func GetSubmissionAssignments(submissionID string) ([]*model.ReviewAssignment, error) {
+	return queryReviewAssignments("SELECT "+reviewAssignmentColumns+" FROM review_assignments WHERE submission_id = ? ORDER BY id", submissionID)
+}
+
+// GetStaleAssignments 获取在指定时间之前指派、仍在等待投票的记录
+func GetStaleAssignments(before int64) ([]*model.ReviewAssignment, error) {
+	return queryReviewAssignments("SELECT "+reviewAssignmentColumns+" FROM review_assignments WHERE status = ? AND assigned_at < ? ORDER BY id", AssignmentActive, before)
+}
+
+// CompleteReviewAssignment 在审核员投票后结束其对该投稿的指派
+func CompleteReviewAssignment(submissionID, reviewerID string) error {
+	_, err := DB.Exec("UPDATE review_assignments SET status = ?, closed_at = ? WHERE submission_id = ? AND reviewer_id = ? AND status = ?",
+		AssignmentDone, time.Now().Unix(), submissionID, reviewerID, AssignmentActive)
+	return err
+}
+
+// CloseReviewAssignments 在投稿审核结束后结束其全部指派
+func CloseReviewAssignments(submissionID string) error {
+	_, err := DB.Exec("UPDATE review_assignments SET status = ?, closed_at = ? WHERE submission_id = ? AND status = ?",
+		AssignmentDone, time.Now().Unix(), submissionID, AssignmentActive)
+	return err
+}
+
+// ExpireReviewAssignment 将超时的指派标记为已转派，返回是否由本次调用完成
+func ExpireReviewAssignment(id int64) (bool, error) {
+	result, err := DB.Exec("UPDATE review_assignments SET status = ?, closed_at = ? WHERE id = ? AND status = ?",
+		AssignmentExpired, time.Now().Unix(), id, AssignmentActive)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// CountActiveAssignments 统计每名审核员当前等待投票的指派数
+func CountActiveAssignments() (map[string]int, error) {
+	rows, err := DB.Query("SELECT reviewer_id, COUNT(*) FROM review_assignments WHERE status = ? GROUP BY reviewer_id", AssignmentActive)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var reviewerID string
+		var count int
+		if err := rows.Scan(&reviewerID, &count); err != nil {
+			return nil, err
+		}
+		counts[reviewerID] = count
+	}
+	return counts, rows.Err()
+}
+
+// LastAssignedReviewer 获取最近一次被指派的审核员，用于轮流指派，没有记录时返回空字符串
+func LastAssignedReviewer() (string, error) {
+	var reviewerID string
+	err := DB.QueryRow("SELECT reviewer_id FROM review_assignments ORDER BY id DESC LIMIT 1").Scan(&reviewerID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return reviewerID, err
+}
+
+// SetReviewerAway 暂停审核员接受指派，until 为恢复时间的 Unix 时间戳，为 0 时直到手动恢复
+func SetReviewerAway(userID string, until int64) error {
+	_, err := DB.Exec(`INSERT INTO reviewer_away (user_id, away_until, created_at) VALUES (?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET away_until = excluded.away_until, created_at = excluded.created_at`,
+		userID, until, time.Now().Unix())
+	return err
+}
+
+// ClearReviewerAway 恢复审核员接受指派，返回其之前是否处于暂停状态
+func ClearReviewerAway(userID string) (bool, error) {
+	result, err := DB.Exec("DELETE FROM reviewer_away WHERE user_id = ?", userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// IsReviewerAway 检查审核员当前是否暂停接受指派
+func IsReviewerAway(userID string) (bool, error) {
+	var until int64
+	err := DB.QueryRow("SELECT away_until FROM reviewer_away WHERE user_id = ?", userID).Scan(&until)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return until == 0 || until > time.Now().Unix(), nil
+}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"amway/model"
+	"time"
+)
+
+const reviewerNoteColumns = `id, user_id, submission_id, author_id, content, created_at`
+
+func scanReviewerNote(scanner rowScanner) (*model.ReviewerNote, error) {
+	var n model.ReviewerNote
+	err := scanner.Scan(&n.ID, &n.UserID, &n.SubmissionID, &n.AuthorID, &n.Content, &n.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// CreateReviewerNote 为用户添加一条审核备注，submissionID 可以为空，返回备注 ID
+func CreateReviewerNote(userID, submissionID, authorID, content string) (int64, error) {
+	result, err := DB.Exec(`INSERT INTO reviewer_notes (user_id, submission_id, author_id, content, created_at)
+		VALUES (?, ?, ?, ?, ?)`, userID, submissionID, authorID, content, time.Now().Unix())
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// GetUserNotes 获取针对用户的全部审核备注，最新的在前
+func GetUserNotes(userID string) ([]*model.ReviewerNote, error) {
+	rows, err := DB.Query("SELECT "+reviewerNoteColumns+" FROM reviewer_notes WHERE user_id = ? ORDER BY id DESC", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notes []*model.ReviewerNote
+	for rows.Next() {
+		n, err := scanReviewerNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, n)
+	}
+	return notes, rows.Err()
+}
@@ -31,12 +31,40 @@ func IncrementFeaturedCount(userID string) error {
 	return err
 }
 
+// DecrementFeaturedCount 减少用户的 featured_count，不会低于 0
+func DecrementFeaturedCount(userID string) error {
+	_, err := DB.Exec("UPDATE users SET featured_count = MAX(featured_count - 1, 0) WHERE user_id = ?", userID)
+	return err
+}
+
 // IncrementRejectedCount 增加用户的 rejected_count
 func IncrementRejectedCount(userID string) error {
 	_, err := DB.Exec("INSERT INTO users (user_id, rejected_count) VALUES (?, 1) ON CONFLICT(user_id) DO UPDATE SET rejected_count = rejected_count + 1", userID)
 	return err
 }
 
+// DecrementRejectedCount 减少用户的 rejected_count，不会低于 0
+func DecrementRejectedCount(userID string) error {
+	_, err := DB.Exec("UPDATE users SET rejected_count = MAX(rejected_count - 1, 0) WHERE user_id = ?", userID)
+	return err
+}
+
+// CountApprovedSubmissions 统计用户未删除的已通过（含精选）投稿数
+func CountApprovedSubmissions(userID string) (int, error) {
+	var count int
+	err := DB.QueryRow(countApprovedSubmissionsSQL, userID).Scan(&count)
+	return count, err
+}
+
+// CountApprovedSubmissionsInTx 在事务中统计用户未删除的已通过（含精选）投稿数
+func CountApprovedSubmissionsInTx(tx *sql.Tx, userID string) (int, error) {
+	var count int
+	err := tx.QueryRow(countApprovedSubmissionsSQL, userID).Scan(&count)
+	return count, err
+}
+
+const countApprovedSubmissionsSQL = "SELECT COUNT(*) FROM recommendations WHERE author_id = ? AND status IN ('approved', 'featured') AND is_deleted = 0"
+
 // CheckUserBanStatus 检查用户当前是否被封禁
 // 它返回两个布尔值：isBanned（如果用户被临时或永久封禁，则为 true）
 // 和 isPermanent（如果封禁是永久性的，则为 true）
@@ -82,6 +110,46 @@ func ApplyPermanentBan(userID string) error {
 	return err
 }
 
+// IsNotifyOptOut 判断用户是否关闭了通过/精选私信
+func IsNotifyOptOut(userID string) (bool, error) {
+	var optOut bool
+	err := DB.QueryRow("SELECT notify_opt_out FROM users WHERE user_id = ?", userID).Scan(&optOut)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return optOut, err
+}
+
+// SetNotifyOptOut 设置用户是否接收通过/精选私信
+func SetNotifyOptOut(userID string, optOut bool) error {
+	_, err := DB.Exec("INSERT INTO users (user_id, notify_opt_out) VALUES (?, ?) ON CONFLICT(user_id) DO UPDATE SET notify_opt_out = excluded.notify_opt_out", userID, optOut)
+	return err
+}
+
+// RevertBan 撤销一次审核封禁：减少封禁计数并解除当前的临时或永久封禁
+func RevertBan(userID string) error {
+	_, err := DB.Exec("UPDATE users SET ban_count = MAX(ban_count - 1, 0), banned_until = NULL, is_permanently_banned = 0 WHERE user_id = ?", userID)
+	return err
+}
+
+// DecrementBanCount 减少用户的封禁计数，不改变当前的封禁状态
+func DecrementBanCount(userID string) error {
+	_, err := DB.Exec("UPDATE users SET ban_count = MAX(ban_count - 1, 0) WHERE user_id = ?", userID)
+	return err
+}
+
+// ClearTemporaryBan 解除结束时间为 bannedUntil 的临时封禁；封禁已被其他处罚覆盖时不做改动
+func ClearTemporaryBan(userID string, bannedUntil int64) error {
+	_, err := DB.Exec("UPDATE users SET banned_until = NULL WHERE user_id = ? AND banned_until = ?", userID, bannedUntil)
+	return err
+}
+
+// ClearPermanentBan 解除用户的永久封禁，保留临时封禁
+func ClearPermanentBan(userID string) error {
+	_, err := DB.Exec("UPDATE users SET is_permanently_banned = 0 WHERE user_id = ?", userID)
+	return err
+}
+
 // LiftBan 解除用户的任何临时或永久封禁
 func LiftBan(userID string) error {
 	_, err := DB.Exec("UPDATE users SET banned_until = NULL, is_permanently_banned = 0 WHERE user_id = ?", userID)
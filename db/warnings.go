@@ -0,0 +1,103 @@
+package db
+
+import (
+	"amway/model"
+	"time"
+)
+
+const (
+	// WarningEscalationBan 表示警告触发了临时封禁，封禁计数随之增加
+	WarningEscalationBan = "ban"
+	// WarningEscalationPermanent 表示警告直接触发了永久封禁，不增加封禁计数
+	WarningEscalationPermanent = "permanent"
+)
+
+const warningColumns = `id, user_id, guild_id, submission_id, reason, issuer_id, escalation, escalation_kind, banned_until, created_at, expires_at`
+
+func scanWarning(scanner rowScanner) (*model.Warning, error) {
+	var w model.Warning
+	err := scanner.Scan(&w.ID, &w.UserID, &w.GuildID, &w.SubmissionID, &w.Reason, &w.IssuerID, &w.Escalation, &w.EscalationKind, &w.BannedUntil, &w.CreatedAt, &w.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// AddWarning 记录一次警告，expiresAt 为 0 时永不过期，返回警告 ID
+func AddWarning(userID, guildID, submissionID, reason, issuerID string, expiresAt int64) (int64, error) {
+	result, err := DB.Exec(`INSERT INTO warnings (user_id, guild_id, submission_id, reason, issuer_id, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`, userID, guildID, submissionID, reason, issuerID, time.Now().Unix(), expiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// GetActiveWarnings 获取用户在指定服务器中尚未过期的警告，最新的在前
+func GetActiveWarnings(userID, guildID string) ([]*model.Warning, error) {
+	rows, err := DB.Query("SELECT "+warningColumns+" FROM warnings WHERE user_id = ? AND guild_id = ? AND (expires_at = 0 OR expires_at > ?) ORDER BY id DESC",
+		userID, guildID, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var warnings []*model.Warning
+	for rows.Next() {
+		w, err := scanWarning(rows)
+		if err != nil {
+			return nil, err
+		}
+		warnings = append(warnings, w)
+	}
+	return warnings, rows.Err()
+}
+
+// SetWarningEscalation 记录警告触发的处罚，bannedUntil 为临时封禁的结束时间，永久封禁时为 0
+func SetWarningEscalation(id int64, escalation, kind string, bannedUntil int64) error {
+	_, err := DB.Exec("UPDATE warnings SET escalation = ?, escalation_kind = ?, banned_until = ? WHERE id = ?", escalation, kind, bannedUntil, id)
+	return err
+}
+
+// GetEscalatedWarnings 获取用户在所有服务器中触发过处罚的警告（包括已过期的，处罚不随警告过期而解除）
+func GetEscalatedWarnings(userID string) ([]*model.Warning, error) {
+	rows, err := DB.Query("SELECT "+warningColumns+" FROM warnings WHERE user_id = ? AND escalation != '' ORDER BY id DESC", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var warnings []*model.Warning
+	for rows.Next() {
+		w, err := scanWarning(rows)
+		if err != nil {
+			return nil, err
+		}
+		warnings = append(warnings, w)
+	}
+	return warnings, rows.Err()
+}
+
+// DeleteSubmissionWarnings 删除因某条投稿产生的警告，返回被删除的警告
+func DeleteSubmissionWarnings(submissionID string) ([]*model.Warning, error) {
+	rows, err := DB.Query("SELECT "+warningColumns+" FROM warnings WHERE submission_id = ?", submissionID)
+	if err != nil {
+		return nil, err
+	}
+	var warnings []*model.Warning
+	for rows.Next() {
+		w, err := scanWarning(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		warnings = append(warnings, w)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	_, err = DB.Exec("DELETE FROM warnings WHERE submission_id = ?", submissionID)
+	return warnings, err
+}
@@ -26,6 +26,21 @@ const (
 	Reconnecting
 )
 
+func (s ConnectionState) String() string {
+	switch s {
+	case Disconnected:
+		return "disconnected"
+	case Connecting:
+		return "connecting"
+	case Connected:
+		return "connected"
+	case Reconnecting:
+		return "reconnecting"
+	default:
+		return "unknown"
+	}
+}
+
 type ReconnectConfig struct {
 	MaxRetries          int
 	BaseDelay           time.Duration
@@ -49,6 +64,7 @@ type GRPCClient struct {
 	// 连接状态管理
 	connectionState int32 // 使用 atomic 操作
 	connectionMutex sync.RWMutex
+	reconnectCount  int64 // 使用 atomic 操作
 
 	// 重连配置
 	reconnectConfig ReconnectConfig
@@ -29,6 +29,16 @@ func (c *GRPCClient) IsConnected() bool {
 	return c.getConnectionState() == Connected
 }
 
+// ConnectionState 返回当前连接状态
+func (c *GRPCClient) ConnectionState() ConnectionState {
+	return c.getConnectionState()
+}
+
+// ReconnectCount 返回自启动以来发起重连的次数
+func (c *GRPCClient) ReconnectCount() int64 {
+	return atomic.LoadInt64(&c.reconnectCount)
+}
+
 func (c *GRPCClient) Connect() error {
 	// 启动健康检查和重连监控
 	go c.startHealthCheck()
@@ -186,6 +196,7 @@ func (c *GRPCClient) triggerReconnect() {
 
 // performReconnect 执行重连
 func (c *GRPCClient) performReconnect() {
+	atomic.AddInt64(&c.reconnectCount, 1)
 	c.setConnectionState(Reconnecting)
 
 	// 清除旧的连接ID
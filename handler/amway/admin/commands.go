@@ -0,0 +1,57 @@
+package amway_admin
+
+import (
+	"amway/bulk"
+	"amway/command"
+	"amway/health"
+	"amway/logger"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleSyncCommands 重新同步斜杠命令并按服务器展示结果
+func handleSyncCommands(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	// 每个作用域需要两次请求，服务器较多时通过批量队列控制节奏
+	results := command.SyncConfiguredPaced(logger.InteractionContext(i), s, bulk.NewFromConfig(nil))
+	allOK := command.LogSyncResults(results)
+	if allOK {
+		health.SetCommandsRegistered()
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: "🔁 命令同步结果",
+		Color: 0x2ecc71,
+	}
+	if !allOK {
+		embed.Color = 0xe74c3c
+	}
+
+	for _, r := range results {
+		var value string
+		switch {
+		case r.Err != nil:
+			value = fmt.Sprintf("❌ %v", r.Err)
+		case r.Changed():
+			value = fmt.Sprintf("✅ 新增: %s\n更新: %s\n移除: %s", joinOrDash(r.Created), joinOrDash(r.Updated), joinOrDash(r.Removed))
+		default:
+			value = fmt.Sprintf("✅ 无变化 (%d 个命令)", len(r.Unchanged))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  r.Scope(),
+			Value: value,
+		})
+	}
+
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{embed},
+	})
+}
+
+func joinOrDash(names []string) string {
+	if len(names) == 0 {
+		return "-"
+	}
+	return "`" + strings.Join(names, "`, `") + "`"
+}
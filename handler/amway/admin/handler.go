@@ -57,10 +57,24 @@ func AmwayAdminCommandHandler(s *discordgo.Session, i *discordgo.InteractionCrea
 			handleDeleteSubmission(s, i, input)
 		case "resend":
 			handleResendSubmission(s, i, input)
+		case "reopen":
+			handleReopenSubmission(s, i, input)
 		case "ban":
 			handleBanUser(s, i, userID, duration)
 		case "lift_ban":
 			handleLiftBan(s, i, userID)
+		case "user_note":
+			handleUserNote(s, i, userID, input)
+		case "jobs":
+			handleListJobs(s, i)
+		case "run_job":
+			handleRunJob(s, i, input)
+		case "outbox":
+			handleListOutbox(s, i)
+		case "retry_outbox":
+			handleRetryOutbox(s, i, input)
+		case "sync_commands":
+			handleSyncCommands(s, i)
 		default:
 			s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
 				Content: utils.StringPtr("❌ 未知的操作类型 "),
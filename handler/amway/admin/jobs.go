@@ -0,0 +1,89 @@
+package amway_admin
+
+import (
+	"amway/scheduler"
+	"amway/utils"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleListJobs 列出所有定时任务及其运行状态
+func handleListJobs(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	statuses := scheduler.List()
+	if len(statuses) == 0 {
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr("ℹ️ 当前没有注册任何定时任务 "),
+		})
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: "⏱️ 定时任务",
+		Color: 0x3498db,
+	}
+
+	for _, status := range statuses {
+		state := "🟢 空闲"
+		if status.Running {
+			state = "🔄 运行中"
+		} else if status.LastError != "" {
+			state = "🔴 上次失败"
+		}
+
+		lastRun := "从未运行"
+		if !status.LastRun.IsZero() {
+			lastRun = fmt.Sprintf("<t:%d:R> (耗时 %s)", status.LastRun.Unix(), status.LastDuration.Round(time.Millisecond))
+		}
+		nextRun := "-"
+		if !status.NextRun.IsZero() {
+			nextRun = fmt.Sprintf("<t:%d:R>", status.NextRun.Unix())
+		}
+
+		value := fmt.Sprintf("%s • `%s`\n上次运行: %s\n下次运行: %s\n累计运行: %d 次", state, status.Schedule, lastRun, nextRun, status.Runs)
+		if status.LastError != "" {
+			value += fmt.Sprintf("\n错误: %s", status.LastError)
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  status.Name,
+			Value: value,
+		})
+	}
+
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{embed},
+	})
+}
+
+// handleRunJob 手动触发一个定时任务
+func handleRunJob(s *discordgo.Session, i *discordgo.InteractionCreate, name string) {
+	if name == "" {
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr("❌ 请在输入中提供任务名称 "),
+		})
+		return
+	}
+
+	err := scheduler.Trigger(name)
+	switch {
+	case errors.Is(err, scheduler.ErrJobNotFound):
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr(fmt.Sprintf("❌ 未找到名为 `%s` 的任务 ", name)),
+		})
+	case errors.Is(err, scheduler.ErrJobRunning):
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr(fmt.Sprintf("ℹ️ 任务 `%s` 正在运行，请稍后再试 ", name)),
+		})
+	case err != nil:
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr(fmt.Sprintf("❌ 触发任务失败：%v", err)),
+		})
+	default:
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr(fmt.Sprintf("✅ 任务 `%s` 已触发，可通过任务列表查看运行结果 ", name)),
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package amway_admin
+
+import (
+	"amway/db"
+	"amway/outbox"
+	"amway/utils"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const outboxListLimit = 10
+
+// handleListOutbox 显示积压统计以及失败或进入死信的动作
+func handleListOutbox(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	counts, err := db.CountOutboxByStatus()
+	if err != nil {
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr(fmt.Sprintf("❌ 查询待执行动作失败：%v", err)),
+		})
+		return
+	}
+
+	items, err := db.GetStuckOutboxItems(outboxListLimit)
+	if err != nil {
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr(fmt.Sprintf("❌ 查询失败的动作失败：%v", err)),
+		})
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: "📮 待执行动作",
+		Description: fmt.Sprintf("⏳ 待执行: %d | ✅ 已完成: %d | 💀 死信: %d",
+			counts[db.OutboxStatusPending], counts[db.OutboxStatusDone], counts[db.OutboxStatusDead]),
+		Color: 0x3498db,
+	}
+
+	if len(items) == 0 {
+		embed.Description += "\n\n没有失败或卡住的动作"
+	}
+
+	for _, item := range items {
+		state := fmt.Sprintf("⏳ 等待重试 <t:%d:R>", item.NextAttemptAt)
+		if item.Status == db.OutboxStatusDead {
+			state = "💀 死信"
+		}
+
+		payload := item.Payload
+		if len(payload) > 200 {
+			payload = payload[:200] + "..."
+		}
+		lastError := item.LastError
+		if len(lastError) > 300 {
+			lastError = lastError[:300] + "..."
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("#%d • %s", item.ID, item.Action),
+			Value: fmt.Sprintf("%s • 已尝试 %d 次 • 创建于 <t:%d:R>\n```json\n%s\n```错误: %s", state, item.Attempts, item.CreatedAt, payload, lastError),
+		})
+	}
+
+	embed.Footer = &discordgo.MessageEmbedFooter{
+		Text: "使用「重试动作」并输入动作ID可立即重新执行",
+	}
+
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{embed},
+	})
+}
+
+// handleRetryOutbox 将失败或进入死信的动作重置为立即执行
+func handleRetryOutbox(s *discordgo.Session, i *discordgo.InteractionCreate, input string) {
+	id, err := strconv.ParseInt(input, 10, 64)
+	if err != nil {
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr("❌ 请在输入中提供有效的动作ID "),
+		})
+		return
+	}
+
+	if err := db.RequeueOutboxItem(id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+				Content: utils.StringPtr(fmt.Sprintf("❌ 未找到可重试的动作 #%d ", id)),
+			})
+			return
+		}
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr(fmt.Sprintf("❌ 重试动作失败：%v", err)),
+		})
+		return
+	}
+
+	outbox.Kick()
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: utils.StringPtr(fmt.Sprintf("✅ 动作 #%d 已重新加入队列 ", id)),
+	})
+}
@@ -0,0 +1,40 @@
+package amway_admin
+
+import (
+	"amway/logger"
+	"amway/utils"
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ReopenSubmission 撤销审核结果并让投稿重新进入审核，由 amway 包在注册处理器时注入以避免循环依赖
+var ReopenSubmission func(ctx context.Context, s *discordgo.Session, submissionID string) (string, error)
+
+// handleReopenSubmission 重新打开已结束审核的投稿
+func handleReopenSubmission(s *discordgo.Session, i *discordgo.InteractionCreate, submissionID string) {
+	if submissionID == "" {
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr("❌ 请提供需要重新审核的投稿ID "),
+		})
+		return
+	}
+	if ReopenSubmission == nil {
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr("❌ 重新审核功能尚未就绪 "),
+		})
+		return
+	}
+
+	summary, err := ReopenSubmission(logger.InteractionContext(i), s, submissionID)
+	if err != nil {
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr(fmt.Sprintf("❌ 重新审核失败：%v", err)),
+		})
+		return
+	}
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: utils.StringPtr(summary),
+	})
+}
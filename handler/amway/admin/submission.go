@@ -3,8 +3,12 @@ package amway_admin
 import (
 	"amway/config"
 	"amway/db"
+	"amway/handler/tools"
 	"amway/utils"
+	"amway/vote"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -124,6 +128,44 @@ func handlePrintSubmission(s *discordgo.Session, i *discordgo.InteractionCreate,
 		})
 	}
 
+	// 投票历史：包括各轮的投票、改票与撤回；盲审中的投稿不显示当前轮的投票
+	blind := submission.Status == "pending" && slices.Contains(config.Cfg.Review.BlindVotingGuilds, submission.GuildID)
+	if history := buildVoteHistoryField(submission.VoteFileID, blind); history != nil {
+		embed.Fields = append(embed.Fields, history)
+	}
+
+	// 投稿人在该服务器中当前有效的警告
+	if warnings, err := db.GetActiveWarnings(submission.UserID, submission.GuildID); err == nil && len(warnings) > 0 {
+		var lines []string
+		for idx, w := range warnings {
+			if idx >= 4 {
+				lines = append(lines, fmt.Sprintf("……以及另外 %d 条", len(warnings)-idx))
+				break
+			}
+			reason := []rune(strings.ReplaceAll(w.Reason, "\n", "；"))
+			if len(reason) > 100 {
+				reason = append(reason[:99], '…')
+			}
+			line := fmt.Sprintf("<t:%d:d> %s", w.CreatedAt, string(reason))
+			if w.Escalation != "" {
+				line += fmt.Sprintf("（%s）", w.Escalation)
+			}
+			lines = append(lines, line)
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("⚠️ 有效警告（%d 次）", len(warnings)),
+			Value: strings.Join(lines, "\n"),
+		})
+	}
+
+	// 审核员对投稿人的备注
+	if notes, err := db.GetUserNotes(submission.UserID); err == nil && len(notes) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "📝 审核备注",
+			Value: tools.FormatReviewerNotes(notes, 1024),
+		})
+	}
+
 	// 推荐内容（截断显示）
 	if submission.RecommendContent != "" {
 		content := submission.RecommendContent
@@ -142,6 +184,77 @@ func handlePrintSubmission(s *discordgo.Session, i *discordgo.InteractionCreate,
 	})
 }
 
+// buildVoteHistoryField 构建投票历史字段，超出字段长度时省略较早的记录；没有投票记录时返回 nil
+// hideCurrentRound 为 true 时当前轮只显示票数，用于盲审中的投稿
+func buildVoteHistoryField(voteFileID string, hideCurrentRound bool) *discordgo.MessageEmbedField {
+	if voteFileID == "" {
+		return nil
+	}
+	voteManager, err := vote.NewManager()
+	if err != nil {
+		return nil
+	}
+	session, err := voteManager.LoadSession(voteFileID)
+	if err != nil {
+		return &discordgo.MessageEmbedField{Name: "🗳️ 投票历史", Value: fmt.Sprintf("读取失败：%v", err)}
+	}
+	// 旧的投票记录没有历史，只有各轮最终的投票
+	events := session.History
+	if len(events) == 0 {
+		for idx, round := range append(session.PreviousRounds, session.Votes) {
+			for _, v := range round {
+				events = append(events, vote.Event{Event: vote.EventCast, Round: idx, VoterID: v.VoterID, Type: v.Type, Reason: v.Reason, Timestamp: v.Timestamp})
+			}
+		}
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	currentRound := len(session.PreviousRounds)
+	var lines []string
+	lastRound := -1
+	for _, e := range events {
+		if hideCurrentRound && e.Round == currentRound {
+			continue
+		}
+		if e.Round != lastRound {
+			label := "当前轮"
+			if e.Round < len(session.PreviousRounds) {
+				label = fmt.Sprintf("第 %d 轮（%s）", e.Round+1, session.RoundOutcome(e.Round))
+			}
+			lines = append(lines, "**"+label+"**")
+			lastRound = e.Round
+		}
+		lines = append(lines, tools.FormatVoteEvent(e))
+	}
+	if hideCurrentRound && len(session.Votes) > 0 {
+		lines = append(lines, "**当前轮**", fmt.Sprintf("盲审进行中，已投 %d 票", len(session.Votes)))
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	// 从最新的记录往前保留，保证字段不超过 Discord 的 1024 字符限制
+	const maxFieldLength = 1000
+	value := ""
+	for idx := len(lines) - 1; idx >= 0; idx-- {
+		candidate := lines[idx]
+		if value != "" {
+			candidate += "\n" + value
+		}
+		if len([]rune(candidate)) > maxFieldLength {
+			value = fmt.Sprintf("……省略 %d 条较早的记录\n", idx+1) + value
+			break
+		}
+		value = candidate
+	}
+	return &discordgo.MessageEmbedField{
+		Name:  "🗳️ 投票历史",
+		Value: value,
+	}
+}
+
 // handleDeleteSubmission 删除（标记）投稿
 func handleDeleteSubmission(s *discordgo.Session, i *discordgo.InteractionCreate, submissionID string) {
 	// 首先检查投稿是否存在
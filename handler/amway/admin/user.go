@@ -2,6 +2,7 @@ package amway_admin
 
 import (
 	"amway/db"
+	"amway/handler/tools"
 	"amway/utils"
 	"fmt"
 	"time"
@@ -76,3 +77,52 @@ func handleLiftBan(s *discordgo.Session, i *discordgo.InteractionCreate, userID
 		Content: utils.StringPtr(fmt.Sprintf("✅ 用户 <@%s> 的封禁已解除 ", userID)),
 	})
 }
+
+// handleUserNote 为用户添加审核备注；未提供备注内容时列出该用户的全部备注
+func handleUserNote(s *discordgo.Session, i *discordgo.InteractionCreate, userID, content string) {
+	if userID == "" {
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr("❌ 请提供需要备注的用户ID "),
+		})
+		return
+	}
+
+	if content != "" {
+		if _, err := db.CreateReviewerNote(userID, "", i.Member.User.ID, content); err != nil {
+			s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+				Content: utils.StringPtr(fmt.Sprintf("❌ 保存备注失败：%v", err)),
+			})
+			return
+		}
+	}
+
+	notes, err := db.GetUserNotes(userID)
+	if err != nil {
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr(fmt.Sprintf("❌ 获取备注失败：%v", err)),
+		})
+		return
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       "📝 审核备注",
+		Description: fmt.Sprintf("用户 <@%s> 的备注，仅审核员可见，会显示在该用户之后投稿的审核消息中", userID),
+		Color:       0x5865F2, // Discord Blurple
+	}
+	if len(notes) == 0 {
+		embed.Description += "\n\n暂无备注，在「输入」中填写内容即可添加"
+	} else {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("共 %d 条", len(notes)),
+			Value: tools.FormatReviewerNotes(notes, 1024),
+		})
+	}
+
+	message := "✅ 备注已添加"
+	if content == "" {
+		message = ""
+	}
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: utils.StringPtr(message),
+		Embeds:  &[]*discordgo.MessageEmbed{embed},
+	})
+}
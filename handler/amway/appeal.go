@@ -0,0 +1,488 @@
+package amway
+
+import (
+	"amway/config"
+	"amway/db"
+	"amway/logger"
+	"amway/model"
+	"amway/notify"
+	"amway/outbox"
+	"amway/utils"
+	"amway/vote"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	appealKindReject = string(notify.KindReject)
+	appealKindBan    = string(notify.KindBan)
+
+	appealDecisionUphold   = "uphold"
+	appealDecisionOverturn = "overturn"
+)
+
+// respondEphemeral replies to an interaction with an ephemeral message.
+func respondEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// AppealButtonHandler handles the appeal button on rejection and ban notifications by opening the appeal modal.
+func AppealButtonHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	parts := strings.Split(i.MessageComponentData().CustomID, ":")
+	if len(parts) != 3 {
+		return
+	}
+	kind, submissionID := parts[1], parts[2]
+	userID := logger.InteractionUserID(i)
+
+	if config.Cfg.Review.AppealChannelID == "" {
+		respondEphemeral(s, i, "❌ 当前未开放申诉")
+		return
+	}
+
+	submission, err := db.GetSubmission(submissionID)
+	if err != nil || submission == nil || submission.UserID != userID {
+		respondEphemeral(s, i, "❌ 找不到可以申诉的投稿")
+		return
+	}
+
+	switch kind {
+	case appealKindReject:
+		if submission.Status != "rejected" {
+			respondEphemeral(s, i, "ℹ️ 该投稿当前不是未通过状态，无需申诉")
+			return
+		}
+	case appealKindBan:
+		if banned, _, err := db.CheckUserBanStatus(userID); err != nil || !banned {
+			respondEphemeral(s, i, "ℹ️ 您当前未被封禁，无需申诉")
+			return
+		}
+	default:
+		return
+	}
+
+	existing, err := db.GetAppealBySubmission(submissionID, kind)
+	if err != nil {
+		logger.FromContext(logger.InteractionContext(i)).Error("failed to check existing appeal", "error", err)
+		respondEphemeral(s, i, "❌ 查询申诉记录时出错，请稍后再试")
+		return
+	}
+	if existing != nil {
+		respondEphemeral(s, i, fmt.Sprintf("ℹ️ 您已经对该结果提交过申诉（状态: %s），请耐心等待管理员处理", appealStatusText(existing.Status)))
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: fmt.Sprintf("appeal_modal:%s:%s", kind, submissionID),
+			Title:    "提交申诉",
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    "appeal_text",
+							Label:       "申诉理由",
+							Style:       discordgo.TextInputParagraph,
+							Placeholder: "请说明您认为审核结果有误的原因",
+							Required:    true,
+							MinLength:   10,
+							MaxLength:   1000,
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+// AppealModalHandler records an appeal and posts it to the appeal channel for admins to resolve.
+func AppealModalHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ModalSubmitData()
+	parts := strings.Split(data.CustomID, ":")
+	if len(parts) != 3 {
+		return
+	}
+	kind, submissionID := parts[1], parts[2]
+	userID := logger.InteractionUserID(i)
+	ctx := logger.With(logger.InteractionContext(i), logger.KeySubmissionID, submissionID)
+	l := logger.FromContext(ctx)
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+
+	go func() {
+		content := data.Components[0].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput).Value
+
+		submission, err := db.GetSubmission(submissionID)
+		if err != nil || submission == nil || submission.UserID != userID {
+			s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+				Content: utils.StringPtr("❌ 找不到可以申诉的投稿"),
+			})
+			return
+		}
+		if existing, err := db.GetAppealBySubmission(submissionID, kind); err == nil && existing != nil {
+			s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+				Content: utils.StringPtr("ℹ️ 您已经对该结果提交过申诉，请耐心等待管理员处理"),
+			})
+			return
+		}
+
+		appealID, err := db.CreateAppeal(userID, submissionID, kind, content)
+		if err != nil {
+			l.Error("failed to create appeal", "error", err)
+			s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+				Content: utils.StringPtr("❌ 提交申诉时出错，请稍后再试"),
+			})
+			return
+		}
+
+		msg, err := s.ChannelMessageSendComplex(config.Cfg.Review.AppealChannelID, &discordgo.MessageSend{
+			Embeds: []*discordgo.MessageEmbed{buildAppealEmbed(appealID, kind, content, submission)},
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.Button{
+							Label:    "维持原结果",
+							Style:    discordgo.SecondaryButton,
+							CustomID: fmt.Sprintf("appeal_resolve:%s:%d", appealDecisionUphold, appealID),
+						},
+						discordgo.Button{
+							Label:    "撤销原结果",
+							Style:    discordgo.SuccessButton,
+							CustomID: fmt.Sprintf("appeal_resolve:%s:%d", appealDecisionOverturn, appealID),
+						},
+					},
+				},
+			},
+		})
+		if err != nil {
+			l.Error("failed to post appeal", "appeal_id", appealID, "error", err)
+		} else if err := db.UpdateAppealMessageID(appealID, msg.ID); err != nil {
+			l.Error("failed to update appeal message ID", "appeal_id", appealID, "error", err)
+		}
+
+		l.Info("appeal submitted", "appeal_id", appealID, "kind", kind)
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr("✅ 申诉已提交，管理员处理后会通知您"),
+		})
+	}()
+}
+
+// buildAppealEmbed builds the appeal card with the submission, its vote history and the appeal text.
+func buildAppealEmbed(appealID int64, kind, content string, submission *model.Submission) *discordgo.MessageEmbed {
+	title := submission.RecommendTitle
+	if title == "" {
+		title = submission.OriginalTitle
+	}
+	kindText := "不通过"
+	if kind == appealKindBan {
+		kindText = "封禁"
+	}
+
+	fields := []*discordgo.MessageEmbedField{
+		{
+			Name:   "申诉人",
+			Value:  fmt.Sprintf("<@%s>", submission.UserID),
+			Inline: true,
+		},
+		{
+			Name:   "申诉结果",
+			Value:  kindText,
+			Inline: true,
+		},
+		{
+			Name:   "投稿",
+			Value:  fmt.Sprintf("**%s** (`%s`)\n%s", title, submission.ID, submission.URL),
+			Inline: false,
+		},
+		{
+			Name:  "安利内容",
+			Value: truncateField(submission.RecommendContent),
+		},
+		{
+			Name:  "投票记录",
+			Value: truncateField(buildVoteHistory(submission)),
+		},
+		{
+			Name:  "申诉理由",
+			Value: truncateField(content),
+		},
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:  "📮 新的申诉",
+		Color:  0xFFA500, // Orange for attention
+		Fields: fields,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("申诉 ID: %d", appealID),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
+
+// buildVoteHistory lists the votes cast on a submission together with their reasons.
+func buildVoteHistory(submission *model.Submission) string {
+	voteManager, err := vote.NewManager()
+	if err != nil || submission.VoteFileID == "" {
+		return "无投票记录"
+	}
+	session, err := voteManager.LoadSession(submission.VoteFileID)
+	if err != nil || len(session.AllVotes()) == 0 {
+		return "无投票记录"
+	}
+	var lines []string
+	for _, v := range session.AllVotes() {
+		line := fmt.Sprintf("<@%s> `%s`", v.VoterID, v.Type)
+		if v.Reason != "" {
+			line += fmt.Sprintf("：%s", v.Reason)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// truncateRunes shortens a string to at most max runes, marking the cut with an ellipsis.
+func truncateRunes(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+// truncateField keeps a value within Discord's embed field limit.
+func truncateField(value string) string {
+	const maxFieldLength = 1024
+	if value == "" {
+		return "-"
+	}
+	return truncateRunes(value, maxFieldLength)
+}
+
+// appealStatusText returns the display text of an appeal status.
+func appealStatusText(status string) string {
+	switch status {
+	case db.AppealStatusUpheld:
+		return "已驳回"
+	case db.AppealStatusOverturned:
+		return "已通过"
+	default:
+		return "待处理"
+	}
+}
+
+// AppealResolveButtonHandler opens the modal in which an admin confirms an appeal decision with an optional note.
+func AppealResolveButtonHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !utils.CheckAuth(i.Member.User.ID, i.Member.Roles) {
+		respondEphemeral(s, i, "❌ 您没有权限执行此操作")
+		return
+	}
+	parts := strings.Split(i.MessageComponentData().CustomID, ":")
+	if len(parts) != 3 {
+		return
+	}
+	decision, appealID := parts[1], parts[2]
+
+	title := "维持原结果"
+	if decision == appealDecisionOverturn {
+		title = "撤销原结果"
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: fmt.Sprintf("appeal_resolve_modal:%s:%s", decision, appealID),
+			Title:    title,
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    "appeal_note",
+							Label:       "给用户的备注 (可选)",
+							Style:       discordgo.TextInputParagraph,
+							Placeholder: "会附在申诉结果通知中",
+							Required:    false,
+							MaxLength:   500,
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+// AppealResolveModalHandler upholds or overturns an appeal and notifies the user.
+func AppealResolveModalHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !utils.CheckAuth(i.Member.User.ID, i.Member.Roles) {
+		respondEphemeral(s, i, "❌ 您没有权限执行此操作")
+		return
+	}
+	data := i.ModalSubmitData()
+	parts := strings.Split(data.CustomID, ":")
+	if len(parts) != 3 {
+		return
+	}
+	decision := parts[1]
+	appealID, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return
+	}
+	note := data.Components[0].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput).Value
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+
+	go func() {
+		content, err := resolveAppeal(s, i, appealID, decision, note)
+		if err != nil {
+			content = fmt.Sprintf("❌ 处理申诉失败: %v", err)
+		}
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr(content),
+		})
+	}()
+}
+
+// resolveAppeal applies an appeal decision. Overturning a rejection approves and publishes the
+// submission, overturning a ban lifts it. The user is notified of the result either way.
+func resolveAppeal(s *discordgo.Session, i *discordgo.InteractionCreate, appealID int64, decision, note string) (string, error) {
+	appeal, err := db.GetAppeal(appealID)
+	if err != nil {
+		return "", err
+	}
+	if appeal == nil {
+		return "", fmt.Errorf("申诉 %d 不存在", appealID)
+	}
+	ctx := logger.With(logger.InteractionContext(i), logger.KeySubmissionID, appeal.SubmissionID, "appeal_id", appealID)
+	l := logger.FromContext(ctx)
+
+	submission, err := db.GetSubmission(appeal.SubmissionID)
+	if err != nil {
+		return "", err
+	}
+	if submission == nil {
+		return "", fmt.Errorf("投稿 %s 不存在或已被删除", appeal.SubmissionID)
+	}
+
+	status := db.AppealStatusUpheld
+	overturned := decision == appealDecisionOverturn
+	if overturned {
+		status = db.AppealStatusOverturned
+	}
+	restoreSubmission := overturned && appeal.Kind == appealKindReject && submission.Status == "rejected"
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	resolved, err := db.ResolveAppealInTx(tx, appealID, status, i.Member.User.ID, note)
+	if err != nil {
+		return "", err
+	}
+	if !resolved {
+		return "ℹ️ 该申诉已经被处理过了", nil
+	}
+
+	// Restoring a rejected submission publishes it like a regular approval.
+	// The status is checked again in the update, the submission may have been reopened meanwhile
+	if restoreSubmission {
+		restoreSubmission, err = db.ApproveRejectedSubmissionInTx(tx, submission.ID, i.Member.User.ID)
+		if err != nil {
+			return "", err
+		}
+	}
+	if restoreSubmission {
+		if err := outbox.EnqueueInTx(tx, outbox.ActionPublish, outbox.PublishPayload{
+			SubmissionID:    submission.ID,
+			ReplyToOriginal: submission.ReplyToOriginal,
+			InteractionID:   logger.InteractionID(ctx),
+		}); err != nil {
+			return "", err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	outbox.Kick()
+
+	if restoreSubmission {
+		if err := db.DecrementRejectedCount(submission.UserID); err != nil {
+			l.Error("failed to decrement rejected count", "error", err)
+		}
+	}
+	if overturned && appeal.Kind == appealKindBan {
+		// The overturned ban no longer counts toward a permanent ban
+		if err := db.RevertBan(appeal.UserID); err != nil {
+			l.Error("failed to revert ban", "target_user_id", appeal.UserID, "error", err)
+			return "", fmt.Errorf("申诉已标记为通过，但解除封禁失败: %w", err)
+		}
+	}
+	l.Info("appeal resolved", "status", status, "kind", appeal.Kind)
+
+	if err := notify.Send(ctx, notify.KindAppeal, appeal.UserID, notify.Data{
+		Submission:       submission,
+		AppealOverturned: overturned,
+		AppealNote:       note,
+	}); err != nil {
+		l.Error("failed to queue appeal notification", "error", err)
+	}
+
+	updateAppealMessage(s, appeal, status, i.Member.User.ID, note)
+
+	if overturned {
+		return "✅ 已撤销原结果并通知用户", nil
+	}
+	return "✅ 已维持原结果并通知用户", nil
+}
+
+// updateAppealMessage records the decision on the appeal card and removes its buttons.
+func updateAppealMessage(s *discordgo.Session, appeal *model.Appeal, status, resolverID, note string) {
+	if appeal.MessageID == "" {
+		return
+	}
+	channelID := config.Cfg.Review.AppealChannelID
+	msg, err := s.ChannelMessage(channelID, appeal.MessageID)
+	if err != nil || len(msg.Embeds) == 0 {
+		return
+	}
+
+	embed := msg.Embeds[0]
+	result := fmt.Sprintf("%s（处理人: <@%s>）", appealStatusText(status), resolverID)
+	if note != "" {
+		result += "\n备注: " + note
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:  "处理结果",
+		Value: truncateField(result),
+	})
+	embed.Color = 0x5865F2 // Discord Blurple
+
+	components := []discordgo.MessageComponent{}
+	s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		Channel:    channelID,
+		ID:         appeal.MessageID,
+		Embeds:     &[]*discordgo.MessageEmbed{embed},
+		Components: &components,
+	})
+}
@@ -0,0 +1,61 @@
+package amway
+
+import (
+	"amway/config"
+	"amway/db"
+	"amway/utils"
+	"amway/vote"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// isBlindVoting reports whether a guild hides votes until a decision is reached.
+func isBlindVoting(guildID string) bool {
+	for _, id := range config.Cfg.Review.BlindVotingGuilds {
+		if id == guildID {
+			return true
+		}
+	}
+	return false
+}
+
+// MyVoteHandler shows a reviewer their own vote on a submission under blind voting.
+func MyVoteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	cacheID := strings.TrimPrefix(i.MessageComponentData().CustomID, "my_vote:")
+	cacheData, found := utils.GetFromCache(cacheID)
+	if !found {
+		respondEphemeral(s, i, "该投稿已结束审核或已过期")
+		return
+	}
+
+	submission, err := db.GetSubmission(cacheData.SubmissionID)
+	if err != nil || submission == nil {
+		respondEphemeral(s, i, "❌ 找不到该投稿")
+		return
+	}
+	voteManager, err := vote.NewManager()
+	if err != nil {
+		respondEphemeral(s, i, "❌ 读取投票记录失败")
+		return
+	}
+	session, err := voteManager.LoadSession(submission.VoteFileID)
+	if err != nil {
+		respondEphemeral(s, i, "❌ 读取投票记录失败")
+		return
+	}
+
+	for _, v := range session.Votes {
+		if v.VoterID != i.Member.User.ID {
+			continue
+		}
+		content := fmt.Sprintf("您对投稿 `%s` 投了 `%s`", submission.ID, v.Type)
+		if v.Reason != "" {
+			content += "\n> 理由: " + v.Reason
+		}
+		respondEphemeral(s, i, content)
+		return
+	}
+	respondEphemeral(s, i, "您还没有对该投稿投票")
+}
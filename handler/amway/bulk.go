@@ -0,0 +1,84 @@
+package amway
+
+import (
+	"amway/bulk"
+	"amway/utils"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// bulkProgressEditInterval limits how often the ephemeral progress reply is edited,
+// so that progress updates do not compete with the bulk work for rate limit budget.
+const bulkProgressEditInterval = 2 * time.Second
+
+// newBulkProgressReporter returns a progress callback that edits the deferred
+// ephemeral reply with "done/total" and a cancel button for the tracked job.
+func newBulkProgressReporter(s *discordgo.Session, i *discordgo.InteractionCreate, label, jobID string) func(bulk.Progress) {
+	var lastEdit time.Time
+	return func(p bulk.Progress) {
+		if p.Done < p.Total && time.Since(lastEdit) < bulkProgressEditInterval {
+			return
+		}
+		lastEdit = time.Now()
+
+		content := fmt.Sprintf("🔄 %s: %d/%d 已完成", label, p.Done, p.Total)
+		if p.Failed > 0 {
+			content += fmt.Sprintf("（失败 %d 个）", p.Failed)
+		}
+
+		components := []discordgo.MessageComponent{}
+		if p.Done < p.Total {
+			components = []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.Button{
+							Label:    "取消",
+							Style:    discordgo.DangerButton,
+							CustomID: "bulk_cancel:" + jobID,
+							Emoji:    &discordgo.ComponentEmoji{Name: "⏹️"},
+						},
+					},
+				},
+			}
+		}
+
+		if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content:    utils.StringPtr(content),
+			Components: &components,
+		}); err != nil {
+			log.Printf("Error updating bulk progress: %v", err)
+		}
+	}
+}
+
+// BulkCancelHandler handles the cancel button shown on bulk operation progress replies.
+func BulkCancelHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !utils.CheckAuth(i.Member.User.ID, i.Member.Roles) {
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "❌ 您没有权限执行此操作",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		return
+	}
+
+	jobID := strings.TrimPrefix(i.MessageComponentData().CustomID, "bulk_cancel:")
+	content := "⏹️ 已请求取消，正在等待进行中的请求结束..."
+	if !bulk.Cancel(jobID) {
+		content = "该批量操作已经结束"
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
@@ -0,0 +1,143 @@
+package amway
+
+import (
+	"amway/config"
+	"amway/db"
+	"amway/logger"
+	"amway/notify"
+	"amway/outbox"
+	"amway/shared"
+	"amway/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// registerOutboxHandlers registers the outbox actions that depend on submission rendering.
+func registerOutboxHandlers() {
+	outbox.RegisterHandler(outbox.ActionPublish, handlePublishAction)
+	outbox.RegisterHandler(outbox.ActionNotifyThread, handleNotifyThreadAction)
+	outbox.RegisterHandler(outbox.ActionAnnounceApproval, handleAnnounceApprovalAction)
+}
+
+// handlePublishAction publishes an approved submission unless it has already been published.
+func handlePublishAction(ctx context.Context, s *discordgo.Session, payload []byte) error {
+	var p outbox.PublishPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return outbox.Permanent(err)
+	}
+	ctx = logger.With(logger.WithInteractionID(ctx, p.InteractionID), logger.KeySubmissionID, p.SubmissionID)
+
+	submission, err := db.GetSubmission(p.SubmissionID)
+	if err != nil {
+		return fmt.Errorf("failed to get submission %s: %w", p.SubmissionID, err)
+	}
+	if submission == nil {
+		return outbox.Permanent(fmt.Errorf("submission %s not found", p.SubmissionID))
+	}
+	if submission.Status != "approved" && submission.Status != "featured" {
+		logger.FromContext(ctx).Info("skipping publication", "status", submission.Status)
+		return nil
+	}
+	if submission.FinalAmwayMessageID != "" {
+		return nil // Already published by a previous attempt
+	}
+
+	_, err = PublishSubmission(ctx, s, submission, p.ReplyToOriginal)
+	return err
+}
+
+// handleNotifyThreadAction replies to the original post unless the reply already exists.
+func handleNotifyThreadAction(ctx context.Context, s *discordgo.Session, payload []byte) error {
+	var p outbox.NotifyThreadPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return outbox.Permanent(err)
+	}
+	ctx = logger.With(logger.WithInteractionID(ctx, p.InteractionID), logger.KeySubmissionID, p.SubmissionID)
+
+	submission, err := db.GetSubmission(p.SubmissionID)
+	if err != nil {
+		return fmt.Errorf("failed to get submission %s: %w", p.SubmissionID, err)
+	}
+	if submission == nil || (submission.Status != "approved" && submission.Status != "featured") {
+		return nil // Deleted or retracted in the meantime, nothing to notify
+	}
+	if submission.ThreadMessageID != "" && submission.ThreadMessageID != "0" {
+		return nil // Already notified by a previous attempt
+	}
+
+	publishMsg := &discordgo.Message{ID: p.PublishMessageID, ChannelID: p.PublishChannelID}
+	return sendNotificationToOriginalPost(ctx, s, submission, publishMsg)
+}
+
+// handleAnnounceApprovalAction grants the author role and notifies the author once a submission is published.
+func handleAnnounceApprovalAction(ctx context.Context, s *discordgo.Session, payload []byte) error {
+	var p outbox.AnnounceApprovalPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return outbox.Permanent(err)
+	}
+	ctx = logger.With(logger.WithInteractionID(ctx, p.InteractionID), logger.KeySubmissionID, p.SubmissionID)
+	l := logger.FromContext(ctx)
+
+	submission, err := db.GetSubmission(p.SubmissionID)
+	if err != nil {
+		return fmt.Errorf("failed to get submission %s: %w", p.SubmissionID, err)
+	}
+	if submission == nil || (submission.Status != "approved" && submission.Status != "featured") {
+		return nil // Deleted or retracted in the meantime
+	}
+	if submission.FinalAmwayMessageID == "" {
+		return fmt.Errorf("submission %s has not been published yet", submission.ID)
+	}
+
+	kind := notify.KindApprove
+	if submission.Status == "featured" {
+		kind = notify.KindFeature
+	}
+	if sent, err := db.HasNotification(submission.ID, string(kind)); err != nil {
+		return fmt.Errorf("failed to check notifications for submission %s: %w", submission.ID, err)
+	} else if sent {
+		return nil // Already announced by a previous attempt
+	}
+
+	data := notify.Data{
+		Submission: submission,
+		PublishURL: fmt.Sprintf("https://discord.com/channels/%s/%s/%s", submission.GuildID, config.Cfg.AmwayBot.Amway.PublishChannelID, submission.FinalAmwayMessageID),
+	}
+	if submission.ThreadMessageID != "" && submission.ThreadMessageID != "0" {
+		if channelID, _, err := utils.GetOriginalPostDetails(submission.URL); err == nil {
+			data.ThreadURL = fmt.Sprintf("https://discord.com/channels/%s/%s/%s", submission.GuildID, channelID, submission.ThreadMessageID)
+		}
+	}
+
+	// 在投稿通过后，分发身份组；失败时交给独立的动作重试，不阻塞通知
+	if shared.GRPCClient != nil {
+		granted, err := shared.GRPCClient.AssignRole(submission.GuildID, "0", submission.UserID)
+		if err != nil {
+			l.Warn("为用户分配身份组失败，稍后重试", "target_user_id", submission.UserID, "error", err)
+			if err := outbox.Enqueue(outbox.ActionAssignRole, outbox.AssignRolePayload{
+				GuildID:  submission.GuildID,
+				ConfigID: "0",
+				UserID:   submission.UserID,
+			}); err != nil {
+				l.Error("为用户排队分配身份组失败", "target_user_id", submission.UserID, "error", err)
+			}
+			data.RolePending = true
+		} else {
+			data.RoleGranted = granted
+		}
+	}
+
+	optOut, err := db.IsNotifyOptOut(submission.UserID)
+	if err != nil {
+		l.Error("failed to check notification preference", "error", err)
+	}
+	if optOut {
+		l.Debug("author opted out of approval notifications")
+		return nil
+	}
+
+	return notify.Send(ctx, kind, submission.UserID, data)
+}
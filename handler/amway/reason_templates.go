@@ -0,0 +1,286 @@
+package amway
+
+import (
+	"amway/db"
+	"amway/logger"
+	"amway/model"
+	"amway/utils"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// maxTemplateOptions is Discord's limit for options in a select menu.
+	maxTemplateOptions = 25
+	// maxTemplateTextLength leaves room for the reviewer's addition within Discord's 1024 character field limit.
+	maxTemplateTextLength = 850
+)
+
+// reasonKindLabels names the template kinds in menus and listings.
+var reasonKindLabels = map[string]string{
+	db.ReasonKindReject: "❌ 不通过",
+	db.ReasonKindBan:    "🔨 封禁",
+}
+
+// buildReasonTemplateRow builds the select menu that starts a reject or ban vote from one of the guild's templates.
+// It returns nil when the guild has no templates.
+func buildReasonTemplateRow(ctx context.Context, guildID, cacheID string) *discordgo.ActionsRow {
+	templates, err := db.GetReasonTemplates(guildID)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to load reason templates", "error", err)
+		return nil
+	}
+	if len(templates) == 0 {
+		return nil
+	}
+
+	var options []discordgo.SelectMenuOption
+	for _, t := range templates {
+		if len(options) >= maxTemplateOptions {
+			break
+		}
+		options = append(options, discordgo.SelectMenuOption{
+			Label:       truncateRunes(fmt.Sprintf("%s · %s", reasonKindLabels[t.Kind], t.Label), 100),
+			Value:       strconv.FormatInt(t.ID, 10),
+			Description: truncateRunes(t.Text, 100),
+		})
+	}
+	return &discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{
+				CustomID:    "reason_template:" + cacheID,
+				Placeholder: "使用理由模板投票…",
+				Options:     options,
+			},
+		},
+	}
+}
+
+// composeTemplateReason builds the vote reason from a template and the reviewer's optional addition.
+// It returns the ID of the template used, or 0 when it falls back to the addition alone because the template is gone.
+func composeTemplateReason(ctx context.Context, templateID, addition string) (string, int64) {
+	addition = strings.TrimSpace(addition)
+	id, err := strconv.ParseInt(templateID, 10, 64)
+	if err != nil {
+		return addition, 0
+	}
+	template, err := db.GetReasonTemplate(id)
+	if err != nil || template == nil {
+		logger.FromContext(ctx).Warn("reason template not found, using the addition only", "template_id", templateID, "error", err)
+		return addition, 0
+	}
+
+	// Templates saved before the length limit may be longer
+	reason := truncateRunes(template.Text, maxTemplateTextLength)
+	if addition != "" {
+		reason += "\n补充说明：" + addition
+	}
+	return reason, id
+}
+
+// recordTemplateUsage counts a template as used once the vote citing it has been recorded.
+func recordTemplateUsage(ctx context.Context, templateID int64) {
+	if templateID == 0 {
+		return
+	}
+	if err := db.IncrementReasonTemplateUsage(templateID); err != nil {
+		logger.FromContext(ctx).Error("failed to count reason template usage", "template_id", templateID, "error", err)
+	}
+}
+
+// ReasonTemplateSelectHandler handles the template menu on review messages by opening the
+// reject or ban modal with the template preselected and an optional addition.
+func ReasonTemplateSelectHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.MessageComponentData()
+	cacheID := strings.TrimPrefix(data.CustomID, "reason_template:")
+	ctx := logger.With(logger.InteractionContext(i), logger.KeyCacheID, cacheID)
+	if len(data.Values) == 0 {
+		return
+	}
+
+	cacheData, found := utils.GetFromCache(cacheID)
+	if !found {
+		respondEphemeral(s, i, "投票请求已过期，请联系开发者确认是否是 bot 重启导致的缓存丢失或者审核超时")
+		return
+	}
+	if voteBlocked(s, i, cacheData) {
+		return
+	}
+
+	id, err := strconv.ParseInt(data.Values[0], 10, 64)
+	if err != nil {
+		return
+	}
+	template, err := db.GetReasonTemplate(id)
+	if err != nil || template == nil || template.GuildID != i.GuildID {
+		respondEphemeral(s, i, "❌ 该理由模板已被删除，请重新选择")
+		return
+	}
+
+	modalPrefix, title := "modal_reject", "不通过理由："+template.Label
+	if template.Kind == db.ReasonKindBan {
+		modalPrefix, title = "modal_ban", "封禁理由："+template.Label
+	}
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: fmt.Sprintf("%s:%s:%d", modalPrefix, cacheID, template.ID),
+			Title:    truncateRunes(title, 45),
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    "reason",
+							Label:       "补充说明（可选）",
+							Style:       discordgo.TextInputParagraph,
+							Placeholder: truncateRunes(template.Text, 100),
+							Required:    false,
+							MaxLength:   128,
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to respond with template modal", "error", err)
+	}
+}
+
+// ReasonTemplateCommandHandler handles the /reason_template command for managing the guild's templates.
+func ReasonTemplateCommandHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		logger.FromContext(logger.InteractionContext(i)).Error("failed to send deferred response", "error", err)
+		return
+	}
+
+	go func() {
+		if !utils.CheckAuth(i.Member.User.ID, i.Member.Roles) {
+			s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+				Content: utils.StringPtr("❌ 您没有权限执行此操作"),
+			})
+			return
+		}
+
+		options := i.ApplicationCommandData().Options
+		if len(options) == 0 {
+			return
+		}
+		subcommand := options[0]
+		var content string
+		switch subcommand.Name {
+		case "add":
+			content = handleAddReasonTemplate(logger.InteractionContext(i), i.GuildID, subcommand.Options)
+		case "remove":
+			content = handleRemoveReasonTemplate(i.GuildID, subcommand.Options)
+		case "list":
+			embed := buildReasonTemplateListEmbed(i.GuildID)
+			s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+				Embeds: &[]*discordgo.MessageEmbed{embed},
+			})
+			return
+		default:
+			content = "❌ 未知的子命令"
+		}
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr(content),
+		})
+	}()
+}
+
+// handleAddReasonTemplate adds a template to the guild's catalogue.
+func handleAddReasonTemplate(ctx context.Context, guildID string, options []*discordgo.ApplicationCommandInteractionDataOption) string {
+	var kind, label, text string
+	for _, opt := range options {
+		switch opt.Name {
+		case "kind":
+			kind = opt.StringValue()
+		case "label":
+			label = strings.TrimSpace(opt.StringValue())
+		case "text":
+			text = strings.TrimSpace(opt.StringValue())
+		}
+	}
+	if _, ok := reasonKindLabels[kind]; !ok || label == "" || text == "" {
+		return "❌ 请提供类型、标签和理由全文"
+	}
+	if len([]rune(text)) > maxTemplateTextLength {
+		return fmt.Sprintf("❌ 理由全文最多 %d 字", maxTemplateTextLength)
+	}
+
+	id, err := db.CreateReasonTemplate(guildID, kind, label, text)
+	if err != nil {
+		return fmt.Sprintf("❌ 添加理由模板失败：%v", err)
+	}
+	logger.FromContext(ctx).Info("reason template added", "kind", kind, "template_id", id)
+	return fmt.Sprintf("✅ 已添加理由模板 `#%d`（%s · %s），新的审核消息将提供该模板", id, reasonKindLabels[kind], label)
+}
+
+// handleRemoveReasonTemplate removes a template from the guild's catalogue.
+func handleRemoveReasonTemplate(guildID string, options []*discordgo.ApplicationCommandInteractionDataOption) string {
+	var id int64
+	for _, opt := range options {
+		if opt.Name == "id" {
+			id = opt.IntValue()
+		}
+	}
+	removed, err := db.DeleteReasonTemplate(guildID, id)
+	if err != nil {
+		return fmt.Sprintf("❌ 删除理由模板失败：%v", err)
+	}
+	if !removed {
+		return fmt.Sprintf("❌ 本服务器没有编号为 `#%d` 的理由模板", id)
+	}
+	return fmt.Sprintf("✅ 已删除理由模板 `#%d`", id)
+}
+
+// buildReasonTemplateListEmbed lists the guild's templates with their usage counts.
+func buildReasonTemplateListEmbed(guildID string) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title: "📝 理由模板",
+		Color: 0x5865F2, // Discord Blurple
+	}
+	templates, err := db.GetReasonTemplates(guildID)
+	if err != nil {
+		embed.Description = fmt.Sprintf("❌ 获取理由模板失败：%v", err)
+		return embed
+	}
+	if len(templates) == 0 {
+		embed.Description = "本服务器还没有理由模板，使用 `/reason_template add` 添加"
+		return embed
+	}
+
+	byKind := make(map[string][]*model.ReasonTemplate)
+	for _, t := range templates {
+		byKind[t.Kind] = append(byKind[t.Kind], t)
+	}
+	for _, kind := range []string{db.ReasonKindReject, db.ReasonKindBan} {
+		if len(byKind[kind]) == 0 {
+			continue
+		}
+		var lines []string
+		for _, t := range byKind[kind] {
+			lines = append(lines, fmt.Sprintf("`#%d` **%s** · 已使用 %d 次\n> %s", t.ID, t.Label, t.UsageCount, truncateRunes(t.Text, 80)))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  reasonKindLabels[kind],
+			Value: truncateField(strings.Join(lines, "\n")),
+		})
+	}
+	if len(templates) > maxTemplateOptions {
+		embed.Footer = &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("审核消息的菜单最多显示前 %d 个模板", maxTemplateOptions),
+		}
+	}
+	return embed
+}
@@ -1,9 +1,13 @@
 package amway
 
 import (
+	"amway/bulk"
+	"amway/config"
 	"amway/db"
+	"amway/logger"
 	"amway/model"
 	"amway/utils"
+	"context"
 	"fmt"
 	"log"
 
@@ -80,40 +84,59 @@ func RebuildCommandHandler(s *discordgo.Session, i *discordgo.InteractionCreate)
 			return
 		}
 
-		// 实际执行重建
-		successCount := 0
-		failedCount := 0
-		var failedIDs []string
+		// 实际执行重建：通过批量队列控制发送节奏
+		ctx, jobID, done := bulk.Track(logger.InteractionContext(i))
+		defer done()
 
+		reviewRoute := "messages:" + config.Cfg.AmwayBot.Amway.ReviewChannelID
+		tasks := make([]bulk.Task, 0, len(submissions))
 		for _, submission := range submissions {
-			if rebuildSubmissionForReview(s, submission) {
-				successCount++
-			} else {
-				failedCount++
-				failedIDs = append(failedIDs, submission.ID)
-			}
+			submission := submission
+			tasks = append(tasks, bulk.Task{
+				Name:  submission.ID,
+				Route: reviewRoute,
+				Run: func(ctx context.Context) error {
+					return rebuildSubmissionForReview(logger.With(ctx, logger.KeySubmissionID, submission.ID), s, submission)
+				},
+			})
 		}
 
+		queue := bulk.NewFromConfig(newBulkProgressReporter(s, i, "重建中", jobID))
+		result := queue.Run(ctx, tasks)
+
 		// 构建结果消息
-		content := fmt.Sprintf("🔄 **重建完成**\n✅ 成功重建: %d 个\n❌ 失败: %d 个\n", successCount, failedCount)
+		title := "🔄 **重建完成**"
+		if result.Canceled {
+			title = "⏹️ **重建已取消**"
+		}
+		content := fmt.Sprintf("%s\n✅ 成功重建: %d 个\n❌ 失败: %d 个\n", title, result.Succeeded, result.Failed)
+		if skipped := result.Total - result.Done; skipped > 0 {
+			content += fmt.Sprintf("⏭️ 未执行: %d 个\n", skipped)
+		}
 
-		if failedCount > 0 {
+		if result.Failed > 0 {
+			failedIDs := make([]string, 0, len(result.Failures))
+			for _, failure := range result.Failures {
+				failedIDs = append(failedIDs, failure.Name)
+			}
 			content += fmt.Sprintf("\n失败的安利ID: %v", failedIDs)
 		}
 
-		if successCount > 0 {
+		if result.Succeeded > 0 {
 			content += "\n\n重建的安利已重新发送到投票器等待审核"
 		}
 
 		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
-			Content: utils.StringPtr(content),
+			Content:    utils.StringPtr(content),
+			Components: &[]discordgo.MessageComponent{},
 		})
 	}()
 }
 
 // rebuildSubmissionForReview 重建单个安利并发送到投票器
-func rebuildSubmissionForReview(s *discordgo.Session, submission *model.Submission) bool {
-	log.Printf("Rebuilding submission %s for review", submission.ID)
+func rebuildSubmissionForReview(ctx context.Context, s *discordgo.Session, submission *model.Submission) error {
+	l := logger.FromContext(ctx)
+	l.Info("rebuilding submission for review")
 
 	// 构建 SubmissionData 用于缓存
 	submissionData := model.SubmissionData{
@@ -126,10 +149,14 @@ func rebuildSubmissionForReview(s *discordgo.Session, submission *model.Submissi
 
 	// 添加到缓存
 	cacheID := utils.AddToCache(submissionData)
+	ctx = logger.With(ctx, logger.KeyCacheID, cacheID)
 
 	// 使用现有的审核函数发送到审核频道
-	SendSubmissionToReviewChannel(s, submission, cacheID)
+	if err := SendSubmissionToReviewChannel(ctx, s, submission, cacheID); err != nil {
+		utils.RemoveFromCache(cacheID)
+		return err
+	}
 
-	log.Printf("Successfully rebuilt and sent submission %s for review with cache ID %s", submission.ID, cacheID)
-	return true
+	logger.FromContext(ctx).Info("rebuilt submission sent for review")
+	return nil
 }
@@ -14,8 +14,12 @@ func RegisterHandlers() {
 
 	// 管理员命令处理器
 	handler.AddCommandHandler(def.AmwayAdminCommand.Name, amway_admin.AmwayAdminCommandHandler)
+	amway_admin.ReopenSubmission = ReopenSubmission
 	handler.AddCommandHandler(def.LookupCommand.Name, LookupCommandHandler)
 	handler.AddCommandHandler(def.RebuildCommand.Name, RebuildCommandHandler)
+	handler.AddCommandHandler(def.ReviewCommand.Name, ReviewCommandHandler)
+	handler.AddCommandHandler(def.ReasonTemplateCommand.Name, ReasonTemplateCommandHandler)
+	handler.AddComponentHandlerPrefix("bulk_cancel:", BulkCancelHandler)
 	handler.AddCommandHandler(def.TestAssignRoleCommand.Name, TestAssignRoleHandler)
 
 	// 两步投稿流程
@@ -34,10 +38,37 @@ func RegisterHandlers() {
 	handler.AddComponentHandlerPrefix("vote:", VoteHandler)
 	handler.AddModalHandler("modal_reject", ModalRejectHandler)
 	handler.AddModalHandler("modal_ban", ModalBanHandler)
+	handler.AddModalHandler("modal_revise", ModalReviseHandler)
+	handler.AddModalHandler("modal_warn", ModalWarnHandler)
+	handler.AddComponentHandler("reason_template", ReasonTemplateSelectHandler)
+	handler.AddComponentHandlerPrefix("extend_deadline:", ExtendDeadlineHandler)
 
 	// 私信通知相关处理器
 	handler.AddComponentHandlerPrefix("select_reason:", SelectReasonHandler)
 	handler.AddComponentHandlerPrefix("send_rejection_dm:", SendRejectionDMHandler)
 	handler.AddComponentHandlerPrefix("select_ban_reason:", SelectBanReasonHandler)
 	handler.AddComponentHandlerPrefix("send_ban_dm:", SendBanDMHandler)
+
+	// 审核员认领投稿，认领期间其他审核员暂不能投票
+	handler.AddComponentHandler("claim", ClaimHandler)
+
+	// 盲审时审核员查看自己的投票
+	handler.AddComponentHandler("my_vote", MyVoteHandler)
+
+	// 审核员对投稿人的备注，投稿人不可见
+	handler.AddComponentHandler("reviewer_note", ReviewerNoteButtonHandler)
+	handler.AddModalHandler("reviewer_note_modal", ReviewerNoteModalHandler)
+
+	// 需修改的投稿由作者修改后重新提交
+	handler.AddComponentHandler("revise_submission", ReviseSubmissionButtonHandler)
+	handler.AddModalHandler("revise_content_modal", ReviseContentModalHandler)
+
+	// 申诉相关处理器
+	handler.AddComponentHandler("appeal", AppealButtonHandler)
+	handler.AddModalHandler("appeal_modal", AppealModalHandler)
+	handler.AddComponentHandler("appeal_resolve", AppealResolveButtonHandler)
+	handler.AddModalHandler("appeal_resolve_modal", AppealResolveModalHandler)
+
+	// 延迟执行的 Discord 副作用
+	registerOutboxHandlers()
 }
@@ -0,0 +1,209 @@
+package amway
+
+import (
+	"amway/config"
+	"amway/db"
+	"amway/logger"
+	"amway/model"
+	"amway/outbox"
+	"amway/utils"
+	"amway/vote"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ReopenSubmission reverts the side effects of a finalized review decision and sends the submission
+// back into review. The votes of the reverted round are kept as a previous round.
+// It returns a summary of what was reverted for the admin.
+func ReopenSubmission(ctx context.Context, s *discordgo.Session, submissionID string) (string, error) {
+	ctx = logger.With(ctx, logger.KeySubmissionID, submissionID)
+	l := logger.FromContext(ctx)
+
+	submission, err := db.GetSubmission(submissionID)
+	if err != nil {
+		return "", fmt.Errorf("获取投稿信息失败：%w", err)
+	}
+	if submission == nil {
+		return "", fmt.Errorf("未找到ID为 %s 的投稿", submissionID)
+	}
+	if submission.Status != "approved" && submission.Status != "featured" && submission.Status != "rejected" {
+		return "", fmt.Errorf("投稿当前状态为 `%s`，只有已通过、精选或未通过的投稿可以重新审核", submission.Status)
+	}
+
+	voteManager, err := vote.NewManager()
+	if err != nil {
+		return "", fmt.Errorf("读取投票记录失败：%w", err)
+	}
+	session, err := voteManager.LoadSession(submission.VoteFileID)
+	if err != nil {
+		return "", fmt.Errorf("读取投票记录失败：%w", err)
+	}
+	session.SubmissionID = submissionID
+
+	// Bans and warnings are stored as rejected submissions, the votes tell them apart
+	outcome := submission.Status
+	if decided, _ := decideOutcome(session.Votes); submission.Status == "rejected" && (decided == "banned" || decided == "warned") {
+		outcome = decided
+	}
+	// Submissions created before the choice was stored only reveal it through the reply in the original post
+	hadThreadReply := submission.ThreadMessageID != "" && submission.ThreadMessageID != "0"
+	replyToOriginal := submission.ReplyToOriginal || hadThreadReply
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return "", fmt.Errorf("开启事务失败：%w", err)
+	}
+	defer tx.Rollback()
+
+	reopened, err := db.ReopenSubmissionInTx(tx, submissionID)
+	if err != nil {
+		return "", fmt.Errorf("更新投稿状态失败：%w", err)
+	}
+	if !reopened {
+		return "", fmt.Errorf("投稿 %s 的状态已发生变化，请重新查询后再试", submissionID)
+	}
+
+	var reverted []string
+	if submission.FinalAmwayMessageID != "" {
+		if err := outbox.EnqueueInTx(tx, outbox.ActionDeleteMessage, outbox.DeleteMessagePayload{
+			ChannelID: config.Cfg.AmwayBot.Amway.PublishChannelID,
+			MessageID: submission.FinalAmwayMessageID,
+		}); err != nil {
+			return "", fmt.Errorf("排队删除发布消息失败：%w", err)
+		}
+		reverted = append(reverted, "删除发布频道中的安利")
+	}
+	if hadThreadReply {
+		if originalChannelID, _, err := utils.GetOriginalPostDetails(submission.URL); err == nil {
+			if err := outbox.EnqueueInTx(tx, outbox.ActionDeleteMessage, outbox.DeleteMessagePayload{
+				ChannelID: originalChannelID,
+				MessageID: submission.ThreadMessageID,
+			}); err != nil {
+				return "", fmt.Errorf("排队删除原帖通知失败：%w", err)
+			}
+			reverted = append(reverted, "删除原帖下的安利通知")
+		} else {
+			l.Warn("failed to parse original post for reopened submission", "url", submission.URL, "error", err)
+		}
+	}
+
+	if outcome == "approved" || outcome == "featured" {
+		revoked, err := enqueueRoleRevocation(tx, submission)
+		if err != nil {
+			return "", fmt.Errorf("排队收回身份组失败：%w", err)
+		}
+		if revoked {
+			reverted = append(reverted, "收回安利身份组")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("提交事务失败：%w", err)
+	}
+	outbox.Kick()
+
+	// User stats are reverted after the status change, so a concurrent reopen cannot revert them twice
+	switch outcome {
+	case "featured":
+		if err := db.DecrementFeaturedCount(submission.UserID); err != nil {
+			l.Error("failed to decrement featured count", "target_user_id", submission.UserID, "error", err)
+		}
+		reverted = append(reverted, "精选次数 -1")
+	case "rejected":
+		if err := db.DecrementRejectedCount(submission.UserID); err != nil {
+			l.Error("failed to decrement rejected count", "target_user_id", submission.UserID, "error", err)
+		}
+		reverted = append(reverted, "被拒次数 -1")
+	case "banned":
+		if err := db.DecrementRejectedCount(submission.UserID); err != nil {
+			l.Error("failed to decrement rejected count", "target_user_id", submission.UserID, "error", err)
+		}
+		if err := db.RevertBan(submission.UserID); err != nil {
+			l.Error("failed to revert ban", "target_user_id", submission.UserID, "error", err)
+		}
+		reverted = append(reverted, "被拒次数 -1", "撤销本次封禁")
+	case "warned":
+		if err := db.DecrementRejectedCount(submission.UserID); err != nil {
+			l.Error("failed to decrement rejected count", "target_user_id", submission.UserID, "error", err)
+		}
+		reverted = append(reverted, "被拒次数 -1")
+		warnings, err := db.DeleteSubmissionWarnings(submissionID)
+		if err != nil {
+			l.Error("failed to revoke warnings", "error", err)
+			break
+		}
+		if len(warnings) > 0 {
+			reverted = append(reverted, "撤销本次警告")
+		}
+		for _, w := range warnings {
+			if w.Escalation != "" && revertWarningEscalation(ctx, w) {
+				reverted = append(reverted, "撤销警告触发的封禁")
+			}
+		}
+	}
+	utils.DeleteAvailableRejectionReasons(submissionID)
+	utils.DeleteAvailableBanReasons(submissionID)
+	// An entry still waiting for its reason DM would otherwise auto-reject the reopened submission on expiry
+	for cacheID, data := range utils.SnapshotCache() {
+		if data.SubmissionID == submissionID {
+			utils.RemoveFromCache(cacheID)
+		}
+	}
+
+	// Keep the reverted votes as history and start the new review with an empty tally
+	session.StartNewRound(vote.OutcomeReopened)
+	if err := voteManager.SaveSession(session); err != nil {
+		l.Error("failed to save vote session", "error", err)
+	}
+
+	submission, err = db.GetSubmission(submissionID)
+	if err != nil || submission == nil {
+		return "", fmt.Errorf("重新读取投稿失败：%v", err)
+	}
+	cacheID := utils.AddToCache(model.SubmissionData{
+		OriginalAuthor:   submission.OriginalAuthor,
+		RecommendTitle:   submission.RecommendTitle,
+		RecommendContent: submission.RecommendContent,
+		ReplyToOriginal:  replyToOriginal,
+		SubmissionID:     submissionID,
+	})
+	ctx = logger.With(ctx, logger.KeyCacheID, cacheID)
+	if err := SendSubmissionToReviewChannel(ctx, s, submission, cacheID); err != nil {
+		utils.RemoveFromCache(cacheID)
+		return "", fmt.Errorf("投稿已退回待审核，但发送到审核频道失败：%w", err)
+	}
+	l.Info("submission reopened", "previous_status", outcome)
+
+	summary := fmt.Sprintf("✅ 投稿 %s 已重新进入审核（原结果：`%s`）", submissionID, outcome)
+	if len(reverted) > 0 {
+		summary += "\n已撤销：" + strings.Join(reverted, "，")
+	}
+	return summary, nil
+}
+
+// enqueueRoleRevocation queues the removal of the role granted for an approved submission.
+// The role stays when the author has other approved submissions or no role is configured for the guild.
+// It must run after the submission has been reopened in the same transaction.
+func enqueueRoleRevocation(tx *sql.Tx, submission *model.Submission) (bool, error) {
+	roleDetail, ok := config.Cfg.RoleConfig[submission.GuildID]["0"]
+	if !ok || roleDetail.GRPCConfig.RoleID == "" {
+		return false, nil
+	}
+	approved, err := db.CountApprovedSubmissionsInTx(tx, submission.UserID)
+	if err != nil {
+		return false, err
+	}
+	if approved > 0 {
+		return false, nil
+	}
+	err = outbox.EnqueueInTx(tx, outbox.ActionRevokeRole, outbox.RevokeRolePayload{
+		GuildID: submission.GuildID,
+		UserID:  submission.UserID,
+		RoleID:  roleDetail.GRPCConfig.RoleID,
+	})
+	return err == nil, err
+}
@@ -0,0 +1,362 @@
+package amway
+
+import (
+	"amway/config"
+	"amway/db"
+	"amway/logger"
+	"amway/model"
+	"amway/outbox"
+	"amway/scheduler"
+	"amway/utils"
+	"amway/vote"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	assignmentRoundRobin  = "round_robin"
+	assignmentLeastLoaded = "least_loaded"
+
+	defaultAssigneesPerSubmission = 2
+	defaultAssignmentTimeout      = 12 * time.Hour
+	assignmentFieldName           = "指派审核员"
+)
+
+func init() {
+	// 被指派的审核员超时未投票时转派给其他审核员
+	scheduler.Register(scheduler.Job{
+		Name:     "review_assignment_sweep",
+		Schedule: scheduler.Every(5 * time.Minute),
+		Run:      reassignStaleAssignments,
+	})
+}
+
+// assignmentEnabled reports whether submissions are assigned to reviewers from the pool.
+func assignmentEnabled() bool {
+	mode := config.Cfg.Review.AssignmentMode
+	return (mode == assignmentRoundRobin || mode == assignmentLeastLoaded) && len(config.Cfg.Review.ReviewerPool) > 0
+}
+
+// assigneesPerSubmission returns how many reviewers each submission is assigned to.
+func assigneesPerSubmission() int {
+	if n := config.Cfg.Review.AssigneesPerSubmission; n > 0 {
+		return n
+	}
+	return defaultAssigneesPerSubmission
+}
+
+// assignmentTimeout returns how long an assigned reviewer has to vote before the submission is reassigned.
+func assignmentTimeout() time.Duration {
+	d, err := time.ParseDuration(config.Cfg.Review.AssignmentTimeout)
+	if err != nil || d <= 0 {
+		return defaultAssignmentTimeout
+	}
+	return d
+}
+
+// pickReviewers chooses up to n reviewers from the pool for a submission, skipping excluded,
+// away and conflicted reviewers. The pool order is rotated for round-robin assignment and
+// sorted by open assignments for least-loaded assignment.
+func pickReviewers(ctx context.Context, submission *model.Submission, n int, exclude map[string]bool) []string {
+	l := logger.FromContext(ctx)
+	pool := config.Cfg.Review.ReviewerPool
+	candidates := make([]string, 0, len(pool))
+
+	switch config.Cfg.Review.AssignmentMode {
+	case assignmentRoundRobin:
+		last, err := db.LastAssignedReviewer()
+		if err != nil {
+			l.Error("failed to load last assigned reviewer", "error", err)
+		}
+		start := 0
+		for idx, id := range pool {
+			if id == last {
+				start = idx + 1
+				break
+			}
+		}
+		for k := range pool {
+			candidates = append(candidates, pool[(start+k)%len(pool)])
+		}
+	case assignmentLeastLoaded:
+		loads, err := db.CountActiveAssignments()
+		if err != nil {
+			l.Error("failed to count open review assignments", "error", err)
+		}
+		candidates = append(candidates, pool...)
+		sort.SliceStable(candidates, func(a, b int) bool {
+			return loads[candidates[a]] < loads[candidates[b]]
+		})
+	}
+
+	var picked []string
+	for _, id := range candidates {
+		if len(picked) >= n {
+			break
+		}
+		if exclude[id] || reviewConflict(id, submission) != "" {
+			continue
+		}
+		away, err := db.IsReviewerAway(id)
+		if err != nil {
+			l.Error("failed to check reviewer away status", "reviewer_id", id, "error", err)
+			continue
+		}
+		if away {
+			continue
+		}
+		picked = append(picked, id)
+		exclude = withExcluded(exclude, id)
+	}
+	return picked
+}
+
+// withExcluded adds a reviewer to an exclusion set, creating the set if needed.
+func withExcluded(exclude map[string]bool, id string) map[string]bool {
+	if exclude == nil {
+		exclude = make(map[string]bool)
+	}
+	exclude[id] = true
+	return exclude
+}
+
+// mentionList formats user IDs as mentions separated by spaces.
+func mentionList(userIDs []string) string {
+	mentions := make([]string, len(userIDs))
+	for idx, id := range userIDs {
+		mentions[idx] = fmt.Sprintf("<@%s>", id)
+	}
+	return strings.Join(mentions, " ")
+}
+
+// applyAssignmentField shows the reviewers currently assigned to a submission on its embed,
+// or removes the field when nobody is assigned.
+func applyAssignmentField(embed *discordgo.MessageEmbed, assignees []string) {
+	fields := embed.Fields[:0]
+	for _, f := range embed.Fields {
+		if f.Name != assignmentFieldName {
+			fields = append(fields, f)
+		}
+	}
+	embed.Fields = fields
+
+	if len(assignees) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  assignmentFieldName,
+			Value: mentionList(assignees),
+		})
+	}
+}
+
+// recordAssignments stores the reviewers a submission was assigned to.
+func recordAssignments(ctx context.Context, submissionID string, assignees []string) {
+	for _, id := range assignees {
+		if err := db.CreateReviewAssignment(submissionID, id); err != nil {
+			logger.FromContext(ctx).Error("failed to record review assignment", "reviewer_id", id, "error", err)
+		}
+	}
+}
+
+// completeAssignment ends a reviewer's assignment once they have voted on the submission.
+func completeAssignment(ctx context.Context, submissionID, voterID string) {
+	if err := db.CompleteReviewAssignment(submissionID, voterID); err != nil {
+		logger.FromContext(ctx).Error("failed to complete review assignment", "voter_id", voterID, "error", err)
+	}
+}
+
+// closeAssignments ends all open assignments of a submission whose review is over.
+func closeAssignments(ctx context.Context, submissionID string) {
+	if err := db.CloseReviewAssignments(submissionID); err != nil {
+		logger.FromContext(ctx).Error("failed to close review assignments", "error", err)
+	}
+}
+
+// reassignStaleAssignments hands submissions over to other reviewers when the assigned reviewer
+// has not voted within the timeout.
+func reassignStaleAssignments(ctx context.Context) error {
+	stale, err := db.GetStaleAssignments(time.Now().Add(-assignmentTimeout()).Unix())
+	if err != nil {
+		return fmt.Errorf("failed to load stale assignments: %w", err)
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	bySubmission := make(map[string][]*model.ReviewAssignment)
+	var order []string
+	for _, a := range stale {
+		if _, ok := bySubmission[a.SubmissionID]; !ok {
+			order = append(order, a.SubmissionID)
+		}
+		bySubmission[a.SubmissionID] = append(bySubmission[a.SubmissionID], a)
+	}
+
+	voteManager, err := vote.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to create vote manager: %w", err)
+	}
+	for _, submissionID := range order {
+		if err := reassignSubmission(ctx, voteManager, submissionID, bySubmission[submissionID]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reassignSubmission replaces the stale assignees of one submission.
+func reassignSubmission(ctx context.Context, voteManager *vote.Manager, submissionID string, stale []*model.ReviewAssignment) error {
+	ctx = logger.With(ctx, logger.KeySubmissionID, submissionID)
+	submission, err := db.GetSubmission(submissionID)
+	if err != nil {
+		return fmt.Errorf("failed to get submission %s: %w", submissionID, err)
+	}
+	if submission == nil || submission.Status != "pending" {
+		closeAssignments(ctx, submissionID)
+		return nil
+	}
+
+	// Reviewers who were ever assigned or have already voted are not picked again
+	exclude := make(map[string]bool)
+	voted := make(map[string]bool)
+	if submission.VoteFileID != "" {
+		if session, err := voteManager.LoadSession(submission.VoteFileID); err == nil {
+			for _, v := range session.Votes {
+				voted[v.VoterID] = true
+				exclude[v.VoterID] = true
+			}
+		}
+	}
+	assignments, err := db.GetSubmissionAssignments(submissionID)
+	if err != nil {
+		return fmt.Errorf("failed to load assignments of submission %s: %w", submissionID, err)
+	}
+	for _, a := range assignments {
+		exclude[a.ReviewerID] = true
+	}
+
+	var lines []string
+	for _, a := range stale {
+		if voted[a.ReviewerID] {
+			// The vote was cast through a path that did not close the assignment
+			completeAssignment(ctx, submissionID, a.ReviewerID)
+			continue
+		}
+		expired, err := db.ExpireReviewAssignment(a.ID)
+		if err != nil {
+			return fmt.Errorf("failed to expire assignment %d: %w", a.ID, err)
+		}
+		if !expired {
+			continue
+		}
+		replacement := pickReviewers(ctx, submission, 1, exclude)
+		if len(replacement) == 0 {
+			lines = append(lines, fmt.Sprintf("<@%s> 超时未投票，暂无可转派的审核员", a.ReviewerID))
+			continue
+		}
+		recordAssignments(ctx, submissionID, replacement)
+		lines = append(lines, fmt.Sprintf("<@%s> 超时未投票，已转派给 <@%s>", a.ReviewerID, replacement[0]))
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	logger.FromContext(ctx).Info("review assignments reassigned", "count", len(lines))
+
+	s := outbox.Session()
+	if s == nil || submission.ReviewMessageID == "" {
+		return nil
+	}
+	var assignees []string
+	if current, err := db.GetSubmissionAssignments(submissionID); err == nil {
+		for _, a := range current {
+			if a.Status == db.AssignmentActive {
+				assignees = append(assignees, a.ReviewerID)
+			}
+		}
+	}
+	notice := &discordgo.MessageSend{
+		Content: "⏰ " + strings.Join(lines, "\n"),
+		// Only the new assignees are pinged
+		AllowedMentions: &discordgo.MessageAllowedMentions{Users: assignees},
+	}
+	// The discussion thread started from the review message shares its ID (see startReviewThread)
+	_, err = s.ChannelMessageSendComplex(submission.ReviewMessageID, notice)
+	if reviewChannelID := config.Cfg.AmwayBot.Amway.ReviewChannelID; err != nil && reviewChannelID != "" {
+		// The thread may not exist if starting it failed, reply to the review message instead
+		logger.FromContext(ctx).Warn("failed to post reassignment to review thread, falling back to review channel", "error", err)
+		notice.Reference = &discordgo.MessageReference{ChannelID: reviewChannelID, MessageID: submission.ReviewMessageID}
+		_, err = s.ChannelMessageSendComplex(reviewChannelID, notice)
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to post reassignment", "error", err)
+	}
+	updateAssignmentField(ctx, submission.ReviewMessageID, assignees)
+	return nil
+}
+
+// updateAssignmentField refreshes the assignee field on a review message.
+func updateAssignmentField(ctx context.Context, messageID string, assignees []string) {
+	s := outbox.Session()
+	reviewChannelID := config.Cfg.AmwayBot.Amway.ReviewChannelID
+	if s == nil || reviewChannelID == "" {
+		return
+	}
+	msg, err := s.ChannelMessage(reviewChannelID, messageID)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to fetch review message for reassignment", "message_id", messageID, "error", err)
+		return
+	}
+	if len(msg.Embeds) == 0 {
+		return
+	}
+	embeds := msg.Embeds
+	applyAssignmentField(embeds[0], assignees)
+	_, err = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		Channel: reviewChannelID,
+		ID:      msg.ID,
+		Embeds:  &embeds,
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to update assignees on review message", "message_id", msg.ID, "error", err)
+	}
+}
+
+// handleReviewAway pauses assignments to the calling reviewer, optionally for a number of hours.
+func handleReviewAway(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	var hours int64
+	for _, opt := range options {
+		if opt.Name == "hours" {
+			hours = opt.IntValue()
+		}
+	}
+	var until int64
+	content := "✅ 已暂停接受审核指派，使用 `/review back` 恢复"
+	if hours > 0 {
+		until = time.Now().Add(time.Duration(hours) * time.Hour).Unix()
+		content = fmt.Sprintf("✅ 已暂停接受审核指派，<t:%d:R>自动恢复", until)
+	}
+	if err := db.SetReviewerAway(i.Member.User.ID, until); err != nil {
+		content = fmt.Sprintf("❌ 设置失败: %v", err)
+	}
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: utils.StringPtr(content),
+	})
+}
+
+// handleReviewBack resumes assignments to the calling reviewer.
+func handleReviewBack(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	content := "✅ 已恢复接受审核指派"
+	wasAway, err := db.ClearReviewerAway(i.Member.User.ID)
+	if err != nil {
+		content = fmt.Sprintf("❌ 设置失败: %v", err)
+	} else if !wasAway {
+		content = "您当前没有暂停接受审核指派"
+	}
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: utils.StringPtr(content),
+	})
+}
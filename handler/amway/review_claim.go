@@ -0,0 +1,188 @@
+package amway
+
+import (
+	"amway/config"
+	"amway/db"
+	"amway/logger"
+	"amway/model"
+	"amway/outbox"
+	"amway/scheduler"
+	"amway/utils"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	defaultClaimWindow = 30 * time.Minute
+	claimFieldName     = "认领状态"
+)
+
+func init() {
+	// 认领超时后释放，并从审核消息上移除认领状态
+	scheduler.Register(scheduler.Job{
+		Name:     "review_claim_sweep",
+		Schedule: scheduler.Every(time.Minute),
+		Run:      releaseExpiredClaims,
+	})
+}
+
+// claimWindow returns how long a claim locks a submission for other reviewers.
+func claimWindow() time.Duration {
+	d, err := time.ParseDuration(config.Cfg.Review.ClaimWindow)
+	if err != nil || d <= 0 {
+		return defaultClaimWindow
+	}
+	return d
+}
+
+// applyClaimField shows the active claim of a cache entry on the submission embed,
+// or removes the claim field when the submission is unclaimed.
+func applyClaimField(embed *discordgo.MessageEmbed, data model.SubmissionData) {
+	fields := embed.Fields[:0]
+	for _, f := range embed.Fields {
+		if f.Name != claimFieldName {
+			fields = append(fields, f)
+		}
+	}
+	embed.Fields = fields
+
+	if holder, claimed := utils.ActiveClaim(data); claimed {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  claimFieldName,
+			Value: fmt.Sprintf("🔒 <@%s> 正在审核，<t:%d:R>自动释放", holder, data.ClaimedUntil.Unix()),
+		})
+	}
+}
+
+// claimBlocksVote returns the explanation shown to a reviewer who cannot vote because
+// someone else holds the claim. Admins are never blocked.
+func claimBlocksVote(i *discordgo.InteractionCreate, data model.SubmissionData) string {
+	holder, claimed := utils.ActiveClaim(data)
+	if !claimed || holder == i.Member.User.ID || utils.CheckAuth(i.Member.User.ID, i.Member.Roles) {
+		return ""
+	}
+	return fmt.Sprintf("🔒 该投稿已被 <@%s> 认领，<t:%d:R>自动释放，请稍后再投票", holder, data.ClaimedUntil.Unix())
+}
+
+// ClaimHandler handles the claim button on review messages. Pressing it again releases the claim,
+// and admins can take over a claim held by someone else.
+func ClaimHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	cacheID := strings.TrimPrefix(i.MessageComponentData().CustomID, "claim:")
+	ctx := logger.With(logger.InteractionContext(i), logger.KeyCacheID, cacheID)
+	userID := i.Member.User.ID
+
+	data, found := utils.GetFromCache(cacheID)
+	if !found {
+		respondEphemeral(s, i, "该投稿已结束审核或已过期")
+		return
+	}
+
+	submission, err := db.GetSubmission(data.SubmissionID)
+	if err != nil || submission == nil {
+		respondEphemeral(s, i, "❌ 找不到该投稿")
+		return
+	}
+	ctx = logger.With(ctx, logger.KeySubmissionID, submission.ID)
+	if conflict := reviewConflict(userID, submission); conflict != "" {
+		respondEphemeral(s, i, conflict)
+		return
+	}
+
+	var notice string
+	holder, claimed := utils.ActiveClaim(data)
+	switch {
+	case claimed && holder == userID:
+		utils.ReleaseClaim(cacheID, userID)
+		data, _ = utils.GetFromCache(cacheID)
+		notice = "✅ 已取消认领"
+	case claimed && !utils.CheckAuth(userID, i.Member.Roles):
+		respondEphemeral(s, i, fmt.Sprintf("🔒 该投稿已被 <@%s> 认领，<t:%d:R>自动释放", holder, data.ClaimedUntil.Unix()))
+		return
+	default:
+		// The claim is checked again under the cache lock, another reviewer may have claimed it meanwhile
+		var granted bool
+		data, granted = utils.ClaimSubmission(cacheID, userID, claimWindow(), utils.CheckAuth(userID, i.Member.Roles))
+		if !granted {
+			if current, taken := utils.ActiveClaim(data); taken {
+				respondEphemeral(s, i, fmt.Sprintf("🔒 该投稿已被 <@%s> 认领，<t:%d:R>自动释放", current, data.ClaimedUntil.Unix()))
+				return
+			}
+			respondEphemeral(s, i, "该投稿已结束审核或已过期")
+			return
+		}
+		notice = fmt.Sprintf("✅ 已认领，<t:%d:R>前其他审核员无法投票，投票后自动释放", data.ClaimedUntil.Unix())
+		if claimed {
+			notice = fmt.Sprintf("✅ 已接管 <@%s> 的认领，<t:%d:R>自动释放", holder, data.ClaimedUntil.Unix())
+		}
+	}
+	logger.FromContext(ctx).Info("review claim updated", "claimed_by", data.ClaimedBy)
+
+	embeds := i.Message.Embeds
+	if len(embeds) > 0 {
+		applyClaimField(embeds[0], data)
+	}
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     embeds,
+			Components: i.Message.Components,
+		},
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to update review message after claim", "error", err)
+		return
+	}
+
+	s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Content: notice,
+		Flags:   discordgo.MessageFlagsEphemeral,
+	})
+}
+
+// releaseExpiredClaims releases timed out claims and removes them from the review messages.
+func releaseExpiredClaims(ctx context.Context) error {
+	released := utils.ReleaseExpiredClaims()
+	if len(released) == 0 {
+		return nil
+	}
+	s := outbox.Session()
+	reviewChannelID := config.Cfg.AmwayBot.Amway.ReviewChannelID
+	if s == nil || reviewChannelID == "" {
+		return nil // The claims are released, the stale field disappears with the next update
+	}
+
+	for cacheID, data := range released {
+		submission, err := db.GetSubmission(data.SubmissionID)
+		if err != nil {
+			return fmt.Errorf("failed to get submission %s: %w", data.SubmissionID, err)
+		}
+		if submission == nil || submission.ReviewMessageID == "" {
+			continue
+		}
+		l := logger.FromContext(logger.With(ctx, logger.KeySubmissionID, submission.ID, logger.KeyCacheID, cacheID))
+		msg, err := s.ChannelMessage(reviewChannelID, submission.ReviewMessageID)
+		if err != nil {
+			l.Error("failed to fetch review message for released claim", "error", err)
+			continue
+		}
+		if len(msg.Embeds) == 0 {
+			continue
+		}
+		embeds := msg.Embeds
+		applyClaimField(embeds[0], data)
+		_, err = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+			Channel: reviewChannelID,
+			ID:      msg.ID,
+			Embeds:  &embeds,
+		})
+		if err != nil {
+			l.Error("failed to remove released claim from review message", "error", err)
+		}
+	}
+	logger.FromContext(ctx).Info("expired review claims released", "count", len(released))
+	return nil
+}
@@ -0,0 +1,40 @@
+package amway
+
+import (
+	"amway/config"
+	"amway/model"
+	"fmt"
+)
+
+// sameAccountHolder reports whether two user IDs belong to the same person,
+// either directly or through a configured alt account group.
+func sameAccountHolder(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	if a == b {
+		return true
+	}
+	for _, group := range config.Cfg.Review.AltAccountGroups {
+		hasA, hasB := false, false
+		for _, id := range group {
+			hasA = hasA || id == a
+			hasB = hasB || id == b
+		}
+		if hasA && hasB {
+			return true
+		}
+	}
+	return false
+}
+
+// reviewConflict returns why a reviewer may not vote on a submission, or an empty string if there is no conflict.
+func reviewConflict(voterID string, submission *model.Submission) string {
+	switch {
+	case sameAccountHolder(voterID, submission.UserID):
+		return fmt.Sprintf("⚠️ 您是投稿 `%s` 的投稿人（或其关联账号），不能参与该投稿的审核", submission.ID)
+	case sameAccountHolder(voterID, submission.OriginalAuthor):
+		return fmt.Sprintf("⚠️ 您是投稿 `%s` 所安利原帖的作者（或其关联账号），不能参与该投稿的审核", submission.ID)
+	}
+	return ""
+}
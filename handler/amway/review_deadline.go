@@ -0,0 +1,274 @@
+package amway
+
+import (
+	"amway/config"
+	"amway/db"
+	"amway/logger"
+	"amway/model"
+	"amway/outbox"
+	"amway/scheduler"
+	"amway/utils"
+	"amway/vote"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	defaultDeadlineExtension = 12 * time.Hour
+	// maxReminderEntries keeps the reminder embed well below Discord's description limit.
+	maxReminderEntries = 15
+	deadlineFieldName  = "审核截止"
+)
+
+func init() {
+	// 待审投稿等待到配置的检查点时提醒审核员
+	scheduler.Register(scheduler.Job{
+		Name:     "review_reminder_sweep",
+		Schedule: scheduler.Every(10 * time.Minute),
+		Run:      sendReviewReminders,
+	})
+}
+
+// reminderCheckpoints returns the configured reminder checkpoints in ascending order,
+// skipping entries that cannot be parsed.
+func reminderCheckpoints(ctx context.Context) []time.Duration {
+	var checkpoints []time.Duration
+	for _, value := range config.Cfg.Review.ReminderCheckpoints {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			logger.FromContext(ctx).Warn("ignoring invalid review reminder checkpoint", "checkpoint", value)
+			continue
+		}
+		checkpoints = append(checkpoints, d)
+	}
+	sort.Slice(checkpoints, func(a, b int) bool { return checkpoints[a] < checkpoints[b] })
+	return checkpoints
+}
+
+// reviewerRoleIDs returns the roles to mention in reminders, falling back to the admin roles.
+func reviewerRoleIDs() []string {
+	if len(config.Cfg.Review.ReviewerRoleIDs) > 0 {
+		return config.Cfg.Review.ReviewerRoleIDs
+	}
+	return config.Cfg.Commands.Auth.AdminsRoles
+}
+
+// deadlineExtension returns how far the extend button pushes back a deadline.
+func deadlineExtension() time.Duration {
+	d, err := time.ParseDuration(config.Cfg.Review.ExtendBy)
+	if err != nil || d <= 0 {
+		return defaultDeadlineExtension
+	}
+	return d
+}
+
+// staleSubmission is a pending submission that has reached a reminder checkpoint.
+type staleSubmission struct {
+	cacheID    string
+	data       model.SubmissionData
+	submission *model.Submission
+	reached    int
+}
+
+// sendReviewReminders pings the reviewer roles about pending submissions that passed a new checkpoint.
+func sendReviewReminders(ctx context.Context) error {
+	s := outbox.Session()
+	if s == nil {
+		return nil // Session not ready yet
+	}
+	checkpoints := reminderCheckpoints(ctx)
+	reviewChannelID := config.Cfg.AmwayBot.Amway.ReviewChannelID
+	if len(checkpoints) == 0 || reviewChannelID == "" {
+		return nil
+	}
+
+	now := time.Now()
+	var stale []staleSubmission
+	for cacheID, data := range utils.SnapshotCache() {
+		if data.SubmissionID == "" || now.After(utils.SubmissionDeadline(data)) {
+			continue // Drafts are not reviewable, and expired entries are left to the auto-reject sweep
+		}
+		reached := 0
+		for _, checkpoint := range checkpoints {
+			if now.Sub(data.CreatedAt) >= checkpoint {
+				reached++
+			}
+		}
+		if reached <= data.RemindersSent {
+			continue
+		}
+
+		submission, err := db.GetSubmission(data.SubmissionID)
+		if err != nil {
+			return fmt.Errorf("failed to get submission %s: %w", data.SubmissionID, err)
+		}
+		if submission == nil || submission.Status != "pending" {
+			continue
+		}
+		stale = append(stale, staleSubmission{cacheID: cacheID, data: data, submission: submission, reached: reached})
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	sort.Slice(stale, func(a, b int) bool { return stale[a].data.CreatedAt.Before(stale[b].data.CreatedAt) })
+
+	voteManager, err := vote.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to create vote manager: %w", err)
+	}
+
+	var lines []string
+	for idx, entry := range stale {
+		if idx >= maxReminderEntries {
+			lines = append(lines, fmt.Sprintf("……以及另外 %d 条待审投稿", len(stale)-maxReminderEntries))
+			break
+		}
+		lines = append(lines, buildReminderLine(ctx, voteManager, entry, now))
+	}
+
+	var mentions []string
+	for _, roleID := range reviewerRoleIDs() {
+		mentions = append(mentions, fmt.Sprintf("<@&%s>", roleID))
+	}
+
+	_, err = s.ChannelMessageSendComplex(reviewChannelID, &discordgo.MessageSend{
+		Content: strings.TrimSpace(strings.Join(mentions, " ") + " 以下投稿等待审核已久，请尽快处理"),
+		Embed: &discordgo.MessageEmbed{
+			Title:       fmt.Sprintf("⏰ 待审投稿提醒（%d 条）", len(stale)),
+			Description: strings.Join(lines, "\n\n"),
+			Color:       0xFFA500, // Orange for attention
+		},
+		AllowedMentions: &discordgo.MessageAllowedMentions{
+			Roles: reviewerRoleIDs(),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send review reminder: %w", err)
+	}
+
+	for _, entry := range stale {
+		utils.MarkRemindersSent(entry.cacheID, entry.reached)
+	}
+	logger.FromContext(ctx).Info("review reminder sent", "count", len(stale))
+	return nil
+}
+
+// buildReminderLine summarizes a stale submission with its waiting time, deadline and votes so far.
+func buildReminderLine(ctx context.Context, voteManager *vote.Manager, entry staleSubmission, now time.Time) string {
+	title := entry.submission.RecommendTitle
+	if title == "" {
+		title = entry.submission.OriginalTitle
+	}
+	line := fmt.Sprintf("**%s** (`%s`)\n已等待 %s，<t:%d:R>自动拒绝",
+		title,
+		entry.submission.ID,
+		formatWaitingTime(now.Sub(entry.data.CreatedAt)),
+		utils.SubmissionDeadline(entry.data).Unix(),
+	)
+
+	session, err := voteManager.LoadSession(entry.submission.VoteFileID)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to load vote session", logger.KeySubmissionID, entry.submission.ID, logger.KeyCacheID, entry.cacheID, "error", err)
+		return line
+	}
+	if len(session.Votes) == 0 {
+		return line + "\n> 尚无投票"
+	}
+	if isBlindVoting(entry.submission.GuildID) {
+		return line + fmt.Sprintf("\n> 已投 %d 票（盲审）", len(session.Votes))
+	}
+	var votes []string
+	for _, v := range session.Votes {
+		votes = append(votes, fmt.Sprintf("<@%s> `%s`", v.VoterID, v.Type))
+	}
+	return line + "\n> " + strings.Join(votes, "，")
+}
+
+// formatWaitingTime formats a waiting time as hours and minutes.
+func formatWaitingTime(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	if hours == 0 {
+		return fmt.Sprintf("%d 分钟", minutes)
+	}
+	return fmt.Sprintf("%d 小时 %d 分钟", hours, minutes)
+}
+
+// buildDeadlineField builds the embed field showing when a pending submission is auto-rejected.
+func buildDeadlineField(deadline time.Time) *discordgo.MessageEmbedField {
+	return &discordgo.MessageEmbedField{
+		Name:  deadlineFieldName,
+		Value: fmt.Sprintf("<t:%d:f>（<t:%d:R>）", deadline.Unix(), deadline.Unix()),
+	}
+}
+
+// ExtendDeadlineHandler handles the admin button that pushes back a submission's review deadline.
+func ExtendDeadlineHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !utils.CheckAuth(i.Member.User.ID, i.Member.Roles) {
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "❌ 您没有权限执行此操作",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		return
+	}
+
+	cacheID := strings.TrimPrefix(i.MessageComponentData().CustomID, "extend_deadline:")
+	ctx := logger.With(logger.InteractionContext(i), logger.KeyCacheID, cacheID)
+	if data, ok := utils.GetFromCache(cacheID); ok {
+		ctx = logger.With(ctx, logger.KeySubmissionID, data.SubmissionID)
+	}
+	deadline, ok := utils.ExtendSubmissionDeadline(cacheID, deadlineExtension())
+	if !ok {
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "该投稿已结束审核或已过期",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		return
+	}
+	logger.FromContext(ctx).Info("review deadline extended", "deadline", deadline.Format(time.RFC3339))
+
+	// Show the new deadline on the submission embed, keeping the vote status embeds as they are
+	embeds := i.Message.Embeds
+	if len(embeds) > 0 {
+		field := buildDeadlineField(deadline)
+		replaced := false
+		for idx, f := range embeds[0].Fields {
+			if f.Name == deadlineFieldName {
+				embeds[0].Fields[idx] = field
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			embeds[0].Fields = append(embeds[0].Fields, field)
+		}
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     embeds,
+			Components: i.Message.Components,
+		},
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to update review message after extending deadline", "error", err)
+		return
+	}
+
+	s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Content: fmt.Sprintf("✅ 审核期限已延长至 <t:%d:f>", deadline.Unix()),
+		Flags:   discordgo.MessageFlagsEphemeral,
+	})
+}
@@ -0,0 +1,227 @@
+package amway
+
+import (
+	"amway/config"
+	"amway/db"
+	"amway/logger"
+	"amway/model"
+	"amway/utils"
+	"amway/vote"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxQueueEntries keeps the queue embed below Discord's description limit.
+const maxQueueEntries = 15
+
+// ReviewCommandHandler handles the /review command and dispatches its subcommands.
+func ReviewCommandHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		logger.FromContext(logger.InteractionContext(i)).Error("failed to send deferred response", "error", err)
+		return
+	}
+
+	go func() {
+		if !utils.IsReviewer(i.Member.User.ID, i.Member.Roles) {
+			s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+				Content: utils.StringPtr("❌ 您没有权限执行此操作"),
+			})
+			return
+		}
+
+		options := i.ApplicationCommandData().Options
+		if len(options) == 0 {
+			return
+		}
+		subcommand := options[0]
+		switch subcommand.Name {
+		case "queue":
+			// The queue stays limited to admins, reviewers only see their own statistics
+			if !utils.CheckAuth(i.Member.User.ID, i.Member.Roles) {
+				s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+					Content: utils.StringPtr("❌ 您没有权限执行此操作"),
+				})
+				return
+			}
+			handleReviewQueue(s, i, subcommand.Options)
+		case "stats":
+			handleReviewStats(s, i, subcommand.Options)
+		case "away":
+			handleReviewAway(s, i, subcommand.Options)
+		case "back":
+			handleReviewBack(s, i)
+		default:
+			s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+				Content: utils.StringPtr("❌ 未知的子命令"),
+			})
+		}
+	}()
+}
+
+// queueEntry is a pending submission together with its current votes.
+type queueEntry struct {
+	submission *model.Submission
+	votes      []vote.Vote
+	votedByMe  bool
+	pinned     bool
+}
+
+// handleReviewQueue lists pending submissions from oldest to newest with their voting progress.
+// Submissions of trusted authors are pinned to the top.
+func handleReviewQueue(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	unvotedOnly := false
+	for _, opt := range options {
+		if opt.Name == "unvoted" {
+			unvotedOnly = opt.BoolValue()
+		}
+	}
+
+	submissions, err := db.GetPendingSubmissions()
+	if err != nil {
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr(fmt.Sprintf("❌ 获取待审核投稿失败: %v", err)),
+		})
+		return
+	}
+
+	voteManager, err := vote.NewManager()
+	if err != nil {
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr(fmt.Sprintf("❌ 读取投票记录失败: %v", err)),
+		})
+		return
+	}
+
+	ctx := logger.InteractionContext(i)
+	callerID := i.Member.User.ID
+	trust := make(map[string]trustLevel)
+	var entries []queueEntry
+	for _, submission := range submissions {
+		level, ok := trust[submission.UserID]
+		if !ok {
+			level = computeTrustLevel(ctx, submission.UserID)
+			trust[submission.UserID] = level
+		}
+		entry := queueEntry{submission: submission, pinned: level >= trustMid}
+		if submission.VoteFileID != "" {
+			session, err := voteManager.LoadSession(submission.VoteFileID)
+			if err != nil {
+				logger.FromContext(ctx).Error("failed to load vote session", logger.KeySubmissionID, submission.ID, "error", err)
+			} else {
+				entry.votes = session.Votes
+			}
+		}
+		for _, v := range entry.votes {
+			if v.VoterID == callerID {
+				entry.votedByMe = true
+				break
+			}
+		}
+		if unvotedOnly && entry.votedByMe {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 {
+		content := "✅ 当前没有待审核的投稿"
+		if unvotedOnly {
+			content = "✅ 所有待审核的投稿您都已投票"
+		}
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr(content),
+		})
+		return
+	}
+
+	sort.SliceStable(entries, func(a, b int) bool {
+		return entries[a].pinned && !entries[b].pinned
+	})
+
+	now := time.Now()
+	var lines []string
+	for idx, entry := range entries {
+		if idx >= maxQueueEntries {
+			lines = append(lines, fmt.Sprintf("……以及另外 %d 条待审核投稿", len(entries)-maxQueueEntries))
+			break
+		}
+		lines = append(lines, buildQueueLine(entry, now))
+	}
+
+	title := fmt.Sprintf("📋 待审核队列（%d 条）", len(entries))
+	if unvotedOnly {
+		title = fmt.Sprintf("📋 待我投票的投稿（%d 条）", len(entries))
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       title,
+		Description: strings.Join(lines, "\n\n"),
+		Color:       0xFFFF00, // Yellow for pending
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "📌 信任投稿人的投稿置顶，其余按等待时间从长到短排列",
+		},
+	}
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{embed},
+	})
+}
+
+// buildQueueLine summarizes a queued submission with its age, votes and a jump link to the review message.
+func buildQueueLine(entry queueEntry, now time.Time) string {
+	submission := entry.submission
+	title := submission.RecommendTitle
+	if title == "" {
+		title = submission.OriginalTitle
+	}
+
+	voteSummary := "尚无投票"
+	if len(entry.votes) > 0 && isBlindVoting(submission.GuildID) {
+		voteSummary = fmt.Sprintf("%d 票（盲审）", len(entry.votes))
+	} else if len(entry.votes) > 0 {
+		counts := make(map[vote.VoteType]int)
+		var order []vote.VoteType
+		for _, v := range entry.votes {
+			if counts[v.Type] == 0 {
+				order = append(order, v.Type)
+			}
+			counts[v.Type]++
+		}
+		var parts []string
+		for _, t := range order {
+			parts = append(parts, fmt.Sprintf("`%s`×%d", t, counts[t]))
+		}
+		voteSummary = fmt.Sprintf("%d 票：%s", len(entry.votes), strings.Join(parts, " "))
+	}
+
+	myVote := "⏳ 我未投票"
+	if entry.votedByMe {
+		myVote = "✅ 我已投票"
+	}
+
+	link := "审核消息未记录"
+	if submission.ReviewMessageID != "" {
+		link = fmt.Sprintf("[跳转到审核消息](https://discord.com/channels/%s/%s/%s)", submission.GuildID, config.Cfg.AmwayBot.Amway.ReviewChannelID, submission.ReviewMessageID)
+	}
+
+	if entry.pinned {
+		title = "📌 " + title
+	}
+
+	return fmt.Sprintf("**%s** (`%s`)\n已等待 %s · %s · %s\n%s",
+		title,
+		submission.ID,
+		formatWaitingTime(now.Sub(time.Unix(submission.Timestamp, 0))),
+		voteSummary,
+		myVote,
+		link,
+	)
+}
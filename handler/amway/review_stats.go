@@ -0,0 +1,252 @@
+package amway
+
+import (
+	"amway/db"
+	"amway/logger"
+	"amway/model"
+	"amway/utils"
+	"amway/vote"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	defaultStatsDays      = 30
+	maxLeaderboardEntries = 15
+)
+
+// reviewerStats aggregates one reviewer's votes over the statistics window.
+type reviewerStats struct {
+	voterID    string
+	votes      int
+	byType     map[vote.VoteType]int
+	decided    int // Votes on submissions that already have a final outcome
+	agreed     int // Votes that matched that outcome
+	voteDelays []time.Duration
+}
+
+// agreementRate returns the share of decided votes that matched the final outcome.
+func (r *reviewerStats) agreementRate() float64 {
+	if r.decided == 0 {
+		return 0
+	}
+	return float64(r.agreed) / float64(r.decided)
+}
+
+// medianDelay returns the median time from submission to vote.
+func (r *reviewerStats) medianDelay() time.Duration {
+	if len(r.voteDelays) == 0 {
+		return 0
+	}
+	delays := append([]time.Duration(nil), r.voteDelays...)
+	sort.Slice(delays, func(a, b int) bool { return delays[a] < delays[b] })
+	mid := len(delays) / 2
+	if len(delays)%2 == 0 {
+		return (delays[mid-1] + delays[mid]) / 2
+	}
+	return delays[mid]
+}
+
+// voteAgreesWithOutcome reports whether a vote matches a submission's final status.
+// The second return value is false while the submission has no final outcome yet.
+func voteAgreesWithOutcome(voteType vote.VoteType, status string) (bool, bool) {
+	switch status {
+	case "approved", "retracted", "post_retracted":
+		// Retracted submissions were approved and published before the author withdrew them
+		return voteType == vote.Pass || voteType == vote.Feature, true
+	case "featured":
+		return voteType == vote.Feature, true
+	case "rejected":
+		// Bans and warnings are stored as rejected submissions
+		return voteType == vote.Reject || voteType == vote.Ban || voteType == vote.Warn, true
+	case "revision":
+		return voteType == vote.Revise, true
+	default:
+		return false, false
+	}
+}
+
+// collectReviewerStats loads the votes of the submissions and aggregates the votes cast since the window start per reviewer.
+func collectReviewerStats(ctx context.Context, submissions []*model.Submission, since time.Time) (map[string]*reviewerStats, error) {
+	voteManager, err := vote.NewManager()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]*reviewerStats)
+	for _, submission := range submissions {
+		session, err := voteManager.LoadSession(submission.VoteFileID)
+		if err != nil {
+			logger.FromContext(ctx).Error("failed to load vote session", logger.KeySubmissionID, submission.ID, "error", err)
+			continue
+		}
+		submittedAt := time.Unix(submission.Timestamp, 0)
+		for idx, round := range append(session.PreviousRounds, session.Votes) {
+			outcome := submission.Status
+			if idx < len(session.PreviousRounds) {
+				// Reopened rounds have no outcome to agree with, their decision was reverted
+				outcome = session.RoundOutcome(idx)
+			}
+			for _, v := range round {
+				if v.Timestamp.Before(since) {
+					continue
+				}
+				r, ok := stats[v.VoterID]
+				if !ok {
+					r = &reviewerStats{voterID: v.VoterID, byType: make(map[vote.VoteType]int)}
+					stats[v.VoterID] = r
+				}
+				r.votes++
+				r.byType[v.Type]++
+				if agreed, decided := voteAgreesWithOutcome(v.Type, outcome); decided {
+					r.decided++
+					if agreed {
+						r.agreed++
+					}
+				}
+				if delay := v.Timestamp.Sub(submittedAt); delay >= 0 {
+					r.voteDelays = append(r.voteDelays, delay)
+				}
+			}
+		}
+	}
+	return stats, nil
+}
+
+// handleReviewStats reports a reviewer's voting statistics, or the guild leaderboard for admins.
+func handleReviewStats(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	days := defaultStatsDays
+	targetID := i.Member.User.ID
+	leaderboard := false
+	for _, opt := range options {
+		switch opt.Name {
+		case "days":
+			days = int(opt.IntValue())
+		case "user":
+			targetID = opt.UserValue(nil).ID
+		case "leaderboard":
+			leaderboard = opt.BoolValue()
+		}
+	}
+
+	if (leaderboard || targetID != i.Member.User.ID) && !utils.CheckAuth(i.Member.User.ID, i.Member.Roles) {
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr("❌ 只有管理员可以查看其他审核员的统计或排行榜"),
+		})
+		return
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	submissions, err := db.GetVotedSubmissions(i.GuildID)
+	if err != nil {
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr(fmt.Sprintf("❌ 获取投稿失败: %v", err)),
+		})
+		return
+	}
+	stats, err := collectReviewerStats(logger.InteractionContext(i), submissions, since)
+	if err != nil {
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr(fmt.Sprintf("❌ 读取投票记录失败: %v", err)),
+		})
+		return
+	}
+
+	var embed *discordgo.MessageEmbed
+	if leaderboard {
+		embed = buildLeaderboardEmbed(stats, days)
+	} else {
+		embed = buildReviewerStatsEmbed(targetID, stats[targetID], days)
+	}
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{embed},
+	})
+}
+
+// buildReviewerStatsEmbed builds the statistics card for a single reviewer.
+func buildReviewerStatsEmbed(voterID string, r *reviewerStats, days int) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       "📊 审核统计",
+		Description: fmt.Sprintf("<@%s> 最近 %d 天内的投票", voterID, days),
+		Color:       0x5865F2, // Discord Blurple
+	}
+	if r == nil || r.votes == 0 {
+		embed.Description += "\n\n该时间段内没有投票记录"
+		return embed
+	}
+
+	var distribution []string
+	for _, t := range []vote.VoteType{vote.Pass, vote.Feature, vote.Reject, vote.Warn, vote.Ban, vote.Revise} {
+		distribution = append(distribution, fmt.Sprintf("`%s` %d", t, r.byType[t]))
+	}
+
+	agreement := "暂无已结束的投稿"
+	if r.decided > 0 {
+		agreement = fmt.Sprintf("%.0f%%（%d/%d）", r.agreementRate()*100, r.agreed, r.decided)
+	}
+
+	embed.Fields = []*discordgo.MessageEmbedField{
+		{
+			Name:   "投票数",
+			Value:  fmt.Sprintf("%d", r.votes),
+			Inline: true,
+		},
+		{
+			Name:   "与最终结果一致",
+			Value:  agreement,
+			Inline: true,
+		},
+		{
+			Name:   "投票用时中位数",
+			Value:  formatWaitingTime(r.medianDelay()),
+			Inline: true,
+		},
+		{
+			Name:  "投票分布",
+			Value: strings.Join(distribution, " · "),
+		},
+	}
+	return embed
+}
+
+// buildLeaderboardEmbed builds the guild-wide reviewer leaderboard ordered by votes cast.
+func buildLeaderboardEmbed(stats map[string]*reviewerStats, days int) *discordgo.MessageEmbed {
+	ranked := make([]*reviewerStats, 0, len(stats))
+	for _, r := range stats {
+		ranked = append(ranked, r)
+	}
+	sort.Slice(ranked, func(a, b int) bool {
+		if ranked[a].votes != ranked[b].votes {
+			return ranked[a].votes > ranked[b].votes
+		}
+		return ranked[a].voterID < ranked[b].voterID
+	})
+
+	var lines []string
+	for idx, r := range ranked {
+		if idx >= maxLeaderboardEntries {
+			lines = append(lines, fmt.Sprintf("……以及另外 %d 位审核员", len(ranked)-maxLeaderboardEntries))
+			break
+		}
+		agreement := "-"
+		if r.decided > 0 {
+			agreement = fmt.Sprintf("%.0f%%", r.agreementRate()*100)
+		}
+		lines = append(lines, fmt.Sprintf("**%d.** <@%s> — %d 票 · 一致率 %s · 用时中位数 %s",
+			idx+1, r.voterID, r.votes, agreement, formatWaitingTime(r.medianDelay())))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "该时间段内没有投票记录")
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("🏆 审核排行榜（最近 %d 天）", days),
+		Description: strings.Join(lines, "\n"),
+		Color:       0x5865F2, // Discord Blurple
+	}
+}
@@ -0,0 +1,123 @@
+package amway
+
+import (
+	"amway/logger"
+	"amway/model"
+	"amway/vote"
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// reviewThreadArchiveMinutes is how long an idle discussion thread stays open (one day).
+	reviewThreadArchiveMinutes = 1440
+	// maxThreadNameLength is Discord's limit for channel and thread names.
+	maxThreadNameLength = 100
+)
+
+// reviewThreadStatusLabels maps final statuses to the prefix used when renaming a closed thread.
+var reviewThreadStatusLabels = map[string]string{
+	"approved": "已通过",
+	"featured": "精选",
+	"rejected": "未通过",
+	"banned":   "封禁",
+	"revision": "需修改",
+	"warned":   "警告",
+}
+
+// reviewVoteLabels describes each vote type in the discussion thread.
+var reviewVoteLabels = map[vote.VoteType]string{
+	vote.Pass:    "✅ 通过",
+	vote.Reject:  "❌ 不通过",
+	vote.Ban:     "🔨 封禁",
+	vote.Feature: "🌟 精选",
+	vote.Revise:  "✏️ 需修改",
+	vote.Warn:    "⚠️ 警告",
+}
+
+// reviewThreadName builds the thread name for a submission, prefixed with the final status once decided.
+func reviewThreadName(submissionID, title, finalStatus string) string {
+	name := fmt.Sprintf("投稿 %s · %s", submissionID, title)
+	if label, ok := reviewThreadStatusLabels[finalStatus]; ok {
+		name = fmt.Sprintf("[%s] %s", label, name)
+	}
+	return truncateRunes(name, maxThreadNameLength)
+}
+
+// startReviewThread opens a discussion thread on a review message.
+// A thread started from a message shares the message's ID, so the review message ID also addresses the thread.
+func startReviewThread(ctx context.Context, s *discordgo.Session, channelID, messageID string, submission *model.Submission) {
+	title := submission.RecommendTitle
+	if title == "" {
+		title = submission.OriginalTitle
+	}
+	_, err := s.MessageThreadStartComplex(channelID, messageID, &discordgo.ThreadStart{
+		Name:                reviewThreadName(submission.ID, title, ""),
+		AutoArchiveDuration: reviewThreadArchiveMinutes,
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to start review thread", "message_id", messageID, "error", err)
+	}
+}
+
+// postToReviewThread sends a message into the discussion thread of a review message.
+// Failures are only logged, the thread is a convenience and must not block voting.
+func postToReviewThread(ctx context.Context, s *discordgo.Session, threadID, content string) {
+	if threadID == "" {
+		return
+	}
+	_, err := s.ChannelMessageSendComplex(threadID, &discordgo.MessageSend{
+		Content: content,
+		// Mention users without pinging them
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+	if err != nil {
+		logger.FromContext(ctx).Warn("failed to post to review thread", "thread_id", threadID, "error", err)
+	}
+}
+
+// mirrorVoteToThread posts a vote and its reason into the discussion thread.
+// Under blind voting only the fact that someone voted is posted.
+func mirrorVoteToThread(ctx context.Context, s *discordgo.Session, threadID string, v vote.Vote, blind bool) {
+	if blind {
+		postToReviewThread(ctx, s, threadID, fmt.Sprintf("<@%s> 已投票", v.VoterID))
+		return
+	}
+	label, ok := reviewVoteLabels[v.Type]
+	if !ok {
+		label = string(v.Type)
+	}
+	content := fmt.Sprintf("<@%s> 投票：**%s**", v.VoterID, label)
+	if v.Reason != "" {
+		content += "\n> " + truncateField(v.Reason)
+	}
+	postToReviewThread(ctx, s, threadID, content)
+}
+
+// closeReviewThread announces the final result in the discussion thread, then renames and archives it.
+// Blind votes are revealed in the announcement, as they were never mirrored individually.
+func closeReviewThread(ctx context.Context, s *discordgo.Session, threadID string, submission *model.Submission, session *vote.Session, finalStatus string, blind bool) {
+	if threadID == "" {
+		return
+	}
+	content := fmt.Sprintf("📌 投票结束，最终结果：`%s`", finalStatus)
+	if blind && len(session.Votes) > 0 {
+		content += "\n" + buildVoteSummary(session.Votes)
+	}
+	postToReviewThread(ctx, s, threadID, content)
+
+	title := submission.RecommendTitle
+	if title == "" {
+		title = submission.OriginalTitle
+	}
+	archived := true
+	_, err := s.ChannelEditComplex(threadID, &discordgo.ChannelEdit{
+		Name:     reviewThreadName(submission.ID, title, finalStatus),
+		Archived: &archived,
+	})
+	if err != nil {
+		logger.FromContext(ctx).Warn("failed to archive review thread", "thread_id", threadID, "error", err)
+	}
+}
@@ -0,0 +1,133 @@
+package amway
+
+import (
+	"amway/db"
+	"amway/handler/tools"
+	"amway/logger"
+	"amway/utils"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	reviewerNotesFieldName = "📝 审核备注"
+	// maxNoteInputLength limits a single note entered through the modal.
+	maxNoteInputLength = 500
+)
+
+// applyReviewerNotesField shows the reviewer notes about a user on a review embed,
+// replacing any notes field already present. Nothing is shown for users without notes.
+func applyReviewerNotesField(ctx context.Context, embed *discordgo.MessageEmbed, userID string) {
+	notes, err := db.GetUserNotes(userID)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to load reviewer notes", "target_user_id", userID, "error", err)
+		return
+	}
+
+	fields := embed.Fields[:0]
+	for _, f := range embed.Fields {
+		if f.Name != reviewerNotesFieldName {
+			fields = append(fields, f)
+		}
+	}
+	embed.Fields = fields
+
+	if len(notes) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  reviewerNotesFieldName,
+			Value: tools.FormatReviewerNotes(notes, 1024),
+		})
+	}
+}
+
+// ReviewerNoteButtonHandler opens the modal for adding a note about the author of a submission.
+func ReviewerNoteButtonHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	submissionID := strings.TrimPrefix(i.MessageComponentData().CustomID, "reviewer_note:")
+	ctx := logger.With(logger.InteractionContext(i), logger.KeySubmissionID, submissionID)
+	if !utils.IsReviewer(i.Member.User.ID, i.Member.Roles) {
+		respondEphemeral(s, i, "❌ 您没有权限执行此操作")
+		return
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: "reviewer_note_modal:" + submissionID,
+			Title:    "添加审核备注",
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    "note",
+							Label:       "备注内容（仅审核员可见）",
+							Style:       discordgo.TextInputParagraph,
+							Placeholder: "例如：该用户之前因剧透被提醒过",
+							Required:    true,
+							MaxLength:   maxNoteInputLength,
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to respond with reviewer note modal", "error", err)
+	}
+}
+
+// ReviewerNoteModalHandler stores a note about the author of a submission and refreshes
+// the notes shown on the review message.
+func ReviewerNoteModalHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ModalSubmitData()
+	submissionID := strings.TrimPrefix(data.CustomID, "reviewer_note_modal:")
+	ctx := logger.With(logger.InteractionContext(i), logger.KeySubmissionID, submissionID)
+
+	if !utils.IsReviewer(i.Member.User.ID, i.Member.Roles) {
+		respondEphemeral(s, i, "❌ 您没有权限执行此操作")
+		return
+	}
+
+	content := strings.TrimSpace(data.Components[0].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput).Value)
+	if content == "" {
+		respondEphemeral(s, i, "❌ 备注内容不能为空")
+		return
+	}
+
+	submission, err := db.GetSubmission(submissionID)
+	if err != nil || submission == nil {
+		respondEphemeral(s, i, "❌ 找不到该投稿")
+		return
+	}
+	if _, err := db.CreateReviewerNote(submission.UserID, submissionID, i.Member.User.ID, content); err != nil {
+		logger.FromContext(ctx).Error("failed to create reviewer note", "error", err)
+		respondEphemeral(s, i, fmt.Sprintf("❌ 保存备注失败：%v", err))
+		return
+	}
+	logger.FromContext(ctx).Info("reviewer note added", "target_user_id", submission.UserID)
+
+	if i.Message == nil || len(i.Message.Embeds) == 0 {
+		respondEphemeral(s, i, "✅ 备注已保存")
+		return
+	}
+	embeds := i.Message.Embeds
+	applyReviewerNotesField(ctx, embeds[0], submission.UserID)
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     embeds,
+			Components: i.Message.Components,
+		},
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to update review message after note", "error", err)
+		return
+	}
+	s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Content: "✅ 备注已保存，该用户之后的投稿也会显示这条备注",
+		Flags:   discordgo.MessageFlagsEphemeral,
+	})
+	postToReviewThread(ctx, s, i.Message.ID, fmt.Sprintf("📝 <@%s> 添加了审核备注：\n> %s", i.Member.User.ID, truncateField(content)))
+}
@@ -0,0 +1,163 @@
+package amway
+
+import (
+	"amway/db"
+	"amway/logger"
+	"amway/model"
+	"amway/utils"
+	"amway/vote"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// previousRevisionNotes returns the revision notes of the last review round, if the submission was sent back before.
+func previousRevisionNotes(submission *model.Submission) []string {
+	if submission.VoteFileID == "" {
+		return nil
+	}
+	voteManager, err := vote.NewManager()
+	if err != nil {
+		return nil
+	}
+	session, err := voteManager.LoadSession(submission.VoteFileID)
+	if err != nil || len(session.PreviousRounds) == 0 {
+		return nil
+	}
+	last := len(session.PreviousRounds) - 1
+	if session.RoundOutcome(last) != vote.OutcomeRevision {
+		return nil // Reopened by an admin, the author was not asked to change anything
+	}
+
+	var notes []string
+	for _, v := range session.PreviousRounds[last] {
+		if v.Type == vote.Revise && v.Reason != "" {
+			notes = append(notes, v.Reason)
+		}
+	}
+	return notes
+}
+
+// ReviseSubmissionButtonHandler handles the edit button on revision notifications by
+// reopening the content modal pre-filled with the current recommendation.
+func ReviseSubmissionButtonHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	parts := strings.Split(i.MessageComponentData().CustomID, ":")
+	if len(parts) != 3 {
+		return
+	}
+	submissionID, replyToOriginal := parts[1], parts[2]
+
+	submission, err := db.GetSubmission(submissionID)
+	if err != nil || submission == nil || submission.UserID != logger.InteractionUserID(i) {
+		respondEphemeral(s, i, "❌ 找不到需要修改的投稿")
+		return
+	}
+	if submission.Status != "revision" {
+		respondEphemeral(s, i, "ℹ️ 该投稿已经重新提交或不再需要修改")
+		return
+	}
+
+	modal := BuildSubmissionContentModal("", submission.RecommendTitle, submission.RecommendContent)
+	modal.Data.CustomID = fmt.Sprintf("revise_content_modal:%s:%s", submissionID, replyToOriginal)
+	modal.Data.Title = "修改安利内容"
+	if err := s.InteractionRespond(i.Interaction, modal); err != nil {
+		logger.FromContext(logger.InteractionContext(i)).Error("failed to open revision modal", "error", err)
+	}
+}
+
+// ReviseContentModalHandler stores the revised content and sends the submission back into review
+// under the same submission ID, keeping the earlier votes as a previous round.
+func ReviseContentModalHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ModalSubmitData()
+	parts := strings.Split(data.CustomID, ":")
+	if len(parts) != 3 {
+		return
+	}
+	submissionID := parts[1]
+	replyToOriginal := parts[2] == "true"
+	userID := logger.InteractionUserID(i)
+	ctx := logger.With(logger.InteractionContext(i), logger.KeySubmissionID, submissionID)
+	l := logger.FromContext(ctx)
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+
+	go func() {
+		if banned, _, err := db.CheckUserBanStatus(userID); err == nil && banned {
+			s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+				Content: utils.StringPtr("❌ 您当前处于封禁状态，无法重新提交"),
+			})
+			return
+		}
+
+		recommendTitle, recommendContent := readSubmissionContentInputs(data)
+		recommendTitle = strings.TrimLeft(recommendTitle, "#")
+		if recommendTitle == "" || recommendContent == "" {
+			s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+				Content: utils.StringPtr("标题和内容都是必填的，请重新提交"),
+			})
+			return
+		}
+
+		updated, err := db.ResubmitRevision(submissionID, userID, recommendTitle, recommendContent)
+		if err != nil {
+			l.Error("failed to store revised submission", "error", err)
+			s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+				Content: utils.StringPtr("❌ 保存修改时出错，请稍后再试"),
+			})
+			return
+		}
+		if !updated {
+			s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+				Content: utils.StringPtr("ℹ️ 该投稿已经重新提交或不再需要修改"),
+			})
+			return
+		}
+
+		submission, err := db.GetSubmission(submissionID)
+		if err != nil || submission == nil {
+			l.Error("failed to reload revised submission", "error", err)
+			return
+		}
+
+		// Archive the previous round so the new review starts from an empty tally
+		voteManager, err := vote.NewManager()
+		if err != nil {
+			l.Error("failed to create vote manager", "error", err)
+		} else if session, err := voteManager.LoadSession(submission.VoteFileID); err != nil {
+			l.Error("failed to load vote session", "vote_file_id", submission.VoteFileID, "error", err)
+		} else {
+			session.SubmissionID = submissionID
+			session.StartNewRound(vote.OutcomeRevision)
+			if err := voteManager.SaveSession(session); err != nil {
+				l.Error("failed to save vote session", "error", err)
+			}
+		}
+
+		cacheID := utils.AddToCache(model.SubmissionData{
+			OriginalAuthor:   submission.OriginalAuthor,
+			RecommendTitle:   submission.RecommendTitle,
+			RecommendContent: submission.RecommendContent,
+			ReplyToOriginal:  replyToOriginal,
+			SubmissionID:     submissionID,
+		})
+		ctx = logger.With(ctx, logger.KeyCacheID, cacheID)
+		if err := SendSubmissionToReviewChannel(ctx, s, submission, cacheID); err != nil {
+			utils.RemoveFromCache(cacheID)
+			s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+				Content: utils.StringPtr("⚠️ 修改已保存，但发送到审核频道失败，请联系管理员处理"),
+			})
+			return
+		}
+
+		l.Info("revised submission resubmitted")
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: utils.StringPtr("✅ 修改已提交，投稿已重新进入审核"),
+		})
+	}()
+}
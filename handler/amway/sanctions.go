@@ -0,0 +1,229 @@
+package amway
+
+import (
+	"amway/config"
+	"amway/db"
+	"amway/logger"
+	"amway/model"
+	"amway/notify"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultBanDuration        = 72 * time.Hour
+	defaultPermanentAfterBans = 3
+)
+
+// sanctionPolicy returns the sanction policy of a guild, falling back to the default policy.
+func sanctionPolicy(guildID string) model.SanctionPolicy {
+	if policy, ok := config.Cfg.Sanctions.Guilds[guildID]; ok {
+		return policy
+	}
+	return config.Cfg.Sanctions.Default
+}
+
+// banDuration returns how long a voted ban lasts under a policy.
+func banDuration(policy model.SanctionPolicy) time.Duration {
+	d, err := time.ParseDuration(policy.BanDuration)
+	if err != nil || d <= 0 {
+		return defaultBanDuration
+	}
+	return d
+}
+
+// permanentAfterBans returns after how many bans a user is banned permanently under a policy.
+func permanentAfterBans(policy model.SanctionPolicy) int {
+	if policy.PermanentAfterBans > 0 {
+		return policy.PermanentAfterBans
+	}
+	return defaultPermanentAfterBans
+}
+
+// formatBanLength describes a ban duration in whole days, or in hours for shorter bans.
+func formatBanLength(d time.Duration) string {
+	if d >= 24*time.Hour && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%d天", int(d/(24*time.Hour)))
+	}
+	return fmt.Sprintf("%g小时", d.Hours())
+}
+
+// applyBan bans a user temporarily and escalates to a permanent ban once the user
+// reaches the policy's ban count. It reports whether the ban became permanent.
+func applyBan(userID string, policy model.SanctionPolicy, duration time.Duration) (*model.User, bool, error) {
+	user, err := db.ApplyBan(userID, duration)
+	if err != nil {
+		return nil, false, err
+	}
+	if user.BanCount < permanentAfterBans(policy) {
+		return user, false, nil
+	}
+	if err := db.ApplyPermanentBan(userID); err != nil {
+		return user, false, fmt.Errorf("failed to apply permanent ban: %w", err)
+	}
+	return user, true, nil
+}
+
+// warningStep returns the highest step of a policy reached by the number of active warnings,
+// or nil when no step is reached.
+func warningStep(policy model.SanctionPolicy, activeWarnings int) *model.WarningStep {
+	var reached *model.WarningStep
+	for idx := range policy.WarningSteps {
+		step := &policy.WarningSteps[idx]
+		if step.Warnings > 0 && step.Warnings <= activeWarnings && (reached == nil || step.Warnings > reached.Warnings) {
+			reached = step
+		}
+	}
+	return reached
+}
+
+// issueWarning records a warning for the author of a submission, applies the ban the guild's
+// policy escalates the active warnings to, and notifies the author.
+func issueWarning(ctx context.Context, submission *model.Submission, reasons []string, issuerID string) {
+	l := logger.FromContext(ctx)
+	policy := sanctionPolicy(submission.GuildID)
+
+	var expiresAt int64
+	if decay, err := time.ParseDuration(policy.WarningDecay); err == nil && decay > 0 {
+		expiresAt = time.Now().Add(decay).Unix()
+	}
+	warningID, err := db.AddWarning(submission.UserID, submission.GuildID, submission.ID, strings.Join(reasons, "\n"), issuerID, expiresAt)
+	if err != nil {
+		l.Error("failed to record warning", "target_user_id", submission.UserID, "error", err)
+		return
+	}
+	warnings, err := db.GetActiveWarnings(submission.UserID, submission.GuildID)
+	if err != nil {
+		l.Error("failed to count active warnings", "target_user_id", submission.UserID, "error", err)
+		return
+	}
+
+	var escalation warningEscalation
+	if step := warningStep(policy, len(warnings)); step != nil {
+		escalation = escalateWarnings(ctx, submission.UserID, policy, step, len(warnings))
+	}
+	if escalation.description != "" {
+		if err := db.SetWarningEscalation(warningID, escalation.description, escalation.kind, escalation.bannedUntil); err != nil {
+			l.Error("failed to record warning escalation", "warning_id", warningID, "error", err)
+		}
+	}
+	l.Info("user warned", "target_user_id", submission.UserID, "active_warnings", len(warnings), "escalation", escalation.kind)
+
+	err = notify.Send(ctx, notify.KindWarn, submission.UserID, notify.Data{
+		Submission:   submission,
+		Reasons:      reasons,
+		WarningCount: len(warnings),
+		Escalation:   escalation.description,
+	})
+	if err != nil {
+		l.Error("failed to queue warning notification", "target_user_id", submission.UserID, "error", err)
+	}
+}
+
+// warningEscalation is the ban a warning step applied, kept on the warning so a reopen can revert it.
+type warningEscalation struct {
+	description string // Shown to the author, empty when no ban was applied
+	kind        string
+	bannedUntil int64 // End of the temporary ban, 0 for permanent steps
+}
+
+// escalateWarnings applies the ban of a warning step and describes it for the author.
+// It returns an empty escalation when the ban could not be applied.
+func escalateWarnings(ctx context.Context, userID string, policy model.SanctionPolicy, step *model.WarningStep, activeWarnings int) warningEscalation {
+	l := logger.FromContext(ctx)
+	if step.Permanent {
+		if err := db.ApplyPermanentBan(userID); err != nil {
+			l.Error("failed to apply permanent ban for warnings", "target_user_id", userID, "error", err)
+			return warningEscalation{}
+		}
+		return warningEscalation{
+			description: fmt.Sprintf("累计 %d 次有效警告，已被永久拒绝投稿", activeWarnings),
+			kind:        db.WarningEscalationPermanent,
+		}
+	}
+
+	duration, err := time.ParseDuration(step.BanDuration)
+	if err != nil || duration <= 0 {
+		l.Error("invalid ban duration in warning step", "warnings", step.Warnings, "ban_duration", step.BanDuration)
+		return warningEscalation{}
+	}
+	user, permanent, err := applyBan(userID, policy, duration)
+	if err != nil {
+		l.Error("failed to apply ban for warnings", "target_user_id", userID, "error", err)
+		if user == nil {
+			return warningEscalation{}
+		}
+	}
+	escalation := warningEscalation{
+		description: fmt.Sprintf("累计 %d 次有效警告，临时封禁%s", activeWarnings, formatBanLength(duration)),
+		kind:        db.WarningEscalationBan,
+		bannedUntil: user.BannedUntil.Int64,
+	}
+	if permanent {
+		escalation.description += fmt.Sprintf("；累计封禁已达 %d 次，已被永久拒绝投稿", user.BanCount)
+	}
+	return escalation
+}
+
+// revertWarningEscalation undoes the ban a revoked warning applied. A temporary ban gives back its
+// ban count; the ban itself is only lifted while no other sanction still requires it.
+// It reports whether anything was lifted.
+func revertWarningEscalation(ctx context.Context, w *model.Warning) bool {
+	l := logger.FromContext(ctx).With("target_user_id", w.UserID, "warning_id", w.ID)
+	kind := w.EscalationKind
+	if kind == "" {
+		// Warnings escalated before the kind was recorded only describe it
+		kind = db.WarningEscalationPermanent
+		if strings.Contains(w.Escalation, "临时封禁") {
+			kind = db.WarningEscalationBan
+		}
+	}
+	if kind == db.WarningEscalationBan {
+		if err := db.DecrementBanCount(w.UserID); err != nil {
+			l.Error("failed to decrement ban count", "error", err)
+			return false
+		}
+	}
+
+	user, err := db.GetUserStats(w.UserID)
+	if err != nil {
+		l.Error("failed to load ban status", "error", err)
+		return false
+	}
+	others, err := db.GetEscalatedWarnings(w.UserID)
+	if err != nil {
+		l.Error("failed to load other escalated warnings", "error", err)
+		return false
+	}
+	now := time.Now().Unix()
+	// Voted bans are not recorded individually, the ban count shows whether they still call for a permanent ban
+	keepPermanent := user.BanCount >= permanentAfterBans(sanctionPolicy(w.GuildID))
+	for _, other := range others {
+		if other.ID == w.ID {
+			continue
+		}
+		if other.EscalationKind == db.WarningEscalationPermanent {
+			keepPermanent = true
+		}
+	}
+
+	lifted := false
+	if user.IsPermanentlyBanned && !keepPermanent {
+		if err := db.ClearPermanentBan(w.UserID); err != nil {
+			l.Error("failed to clear permanent ban", "error", err)
+		} else {
+			lifted = true
+		}
+	}
+	// A later ban replaces banned_until, so a different end time belongs to another sanction
+	if kind == db.WarningEscalationBan && w.BannedUntil > now && user.BannedUntil.Valid && user.BannedUntil.Int64 == w.BannedUntil {
+		if err := db.ClearTemporaryBan(w.UserID, w.BannedUntil); err != nil {
+			l.Error("failed to clear temporary ban", "error", err)
+		} else {
+			lifted = true
+		}
+	}
+	return lifted
+}
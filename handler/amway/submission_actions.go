@@ -2,17 +2,44 @@ package amway
 
 import (
 	"amway/db"
+	"amway/logger"
+	"amway/metrics"
+	"amway/model"
+	"amway/notify"
 	"amway/utils"
 	"amway/vote"
+	"context"
 	"fmt"
 	"log"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
 
+// voteBlocked answers with an ephemeral explanation and returns true if the voter may not vote
+// on the submission, either because of a conflict of interest or because someone else claimed it.
+func voteBlocked(s *discordgo.Session, i *discordgo.InteractionCreate, cacheData model.SubmissionData) bool {
+	voterID := i.Member.User.ID
+	l := logger.FromContext(logger.With(logger.InteractionContext(i), logger.KeySubmissionID, cacheData.SubmissionID))
+	submission, err := db.GetSubmission(cacheData.SubmissionID)
+	if err != nil || submission == nil {
+		l.Error("failed to load submission for conflict check", "error", err)
+		respondEphemeral(s, i, "❌ 找不到该投稿")
+		return true
+	}
+	if notice := reviewConflict(voterID, submission); notice != "" {
+		l.Info("conflicted vote rejected", "voter_id", voterID)
+		respondEphemeral(s, i, notice)
+		return true
+	}
+	if notice := claimBlocksVote(i, cacheData); notice != "" {
+		respondEphemeral(s, i, notice)
+		return true
+	}
+	return false
+}
+
 // VoteHandler handles all voting interactions.
 func VoteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	parts := strings.Split(i.MessageComponentData().CustomID, ":")
@@ -39,6 +66,12 @@ func VoteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 
 	submissionID := cacheData.SubmissionID
 
+	// Retracting is always allowed, new votes need a reviewer without a conflict of interest
+	// and wait until the claim is released
+	if voteType != "remove" && voteBlocked(s, i, cacheData) {
+		return
+	}
+
 	switch voteType {
 	case "remove":
 		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
@@ -49,7 +82,7 @@ func VoteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 			return
 		}
 
-		go processVoteRemoval(s, i, submissionID, voterID, cacheID)
+		go processVoteRemoval(logger.InteractionContext(i), s, i, submissionID, voterID, cacheID)
 		return
 	case vote.Reject:
 		// Show a modal for the rejection reason
@@ -107,6 +140,62 @@ func VoteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 			log.Printf("Error responding with ban modal: %v", err)
 		}
 		return // Stop processing, wait for modal submission
+	case vote.Warn:
+		// Show a modal for the warning sent to the author
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseModal,
+			Data: &discordgo.InteractionResponseData{
+				CustomID: "modal_warn:" + cacheID,
+				Title:    "输入警告理由",
+				Components: []discordgo.MessageComponent{
+					discordgo.ActionsRow{
+						Components: []discordgo.MessageComponent{
+							discordgo.TextInput{
+								CustomID:    "reason",
+								Label:       "警告理由",
+								Style:       discordgo.TextInputParagraph,
+								Placeholder: "请输入警告作者的理由...",
+								Required:    true,
+								MinLength:   8,
+								MaxLength:   128,
+							},
+						},
+					},
+				},
+			},
+		})
+		if err != nil {
+			log.Printf("Error responding with warn modal: %v", err)
+		}
+		return // Stop processing, wait for modal submission
+	case vote.Revise:
+		// Show a modal for the notes the author should address
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseModal,
+			Data: &discordgo.InteractionResponseData{
+				CustomID: "modal_revise:" + cacheID,
+				Title:    "输入修改意见",
+				Components: []discordgo.MessageComponent{
+					discordgo.ActionsRow{
+						Components: []discordgo.MessageComponent{
+							discordgo.TextInput{
+								CustomID:    "reason",
+								Label:       "修改意见",
+								Style:       discordgo.TextInputParagraph,
+								Placeholder: "请告诉作者需要修改的地方...",
+								Required:    true,
+								MinLength:   4,
+								MaxLength:   256,
+							},
+						},
+					},
+				},
+			},
+		})
+		if err != nil {
+			log.Printf("Error responding with revise modal: %v", err)
+		}
+		return // Stop processing, wait for modal submission
 	}
 
 	// For other vote types, defer the update and process in the background.
@@ -118,18 +207,27 @@ func VoteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		return
 	}
 
-	go processVote(s, i, submissionID, voterID, voteType, "", cacheData.ReplyToOriginal, cacheID)
+	go processVote(logger.InteractionContext(i), s, i, submissionID, voterID, voteType, "", cacheData.ReplyToOriginal, cacheID)
 }
 
 // ModalRejectHandler handles the submission of the rejection reason modal.
 func ModalRejectHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	parts := strings.Split(i.ModalSubmitData().CustomID, ":")
-	if len(parts) != 2 {
+	if len(parts) != 2 && len(parts) != 3 {
 		return // Invalid custom ID
 	}
 	cacheID := parts[1]
 	voterID := i.Member.User.ID
 	reason := i.ModalSubmitData().Components[0].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput).Value
+	var templateID int64
+	if len(parts) == 3 {
+		// Opened from the reason template menu, the text input only holds an optional addition
+		reason, templateID = composeTemplateReason(logger.With(logger.InteractionContext(i), logger.KeyCacheID, cacheID), parts[2], reason)
+	}
+	if strings.TrimSpace(reason) == "" {
+		respondEphemeral(s, i, "❌ 理由模板已被删除，请重新选择或填写理由")
+		return
+	}
 
 	// Get submission data from cache
 	cacheData, found := utils.GetFromCache(cacheID)
@@ -145,6 +243,12 @@ func ModalRejectHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		return
 	}
 
+	// The claim may have been taken while the modal was open
+	if notice := claimBlocksVote(i, cacheData); notice != "" {
+		respondEphemeral(s, i, notice)
+		return
+	}
+
 	submissionID := cacheData.SubmissionID
 
 	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
@@ -155,7 +259,12 @@ func ModalRejectHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		return
 	}
 
-	go processVote(s, i, submissionID, voterID, vote.Reject, reason, cacheData.ReplyToOriginal, cacheID)
+	go func() {
+		ctx := logger.InteractionContext(i)
+		if processVote(ctx, s, i, submissionID, voterID, vote.Reject, reason, cacheData.ReplyToOriginal, cacheID) {
+			recordTemplateUsage(ctx, templateID)
+		}
+	}()
 }
 
 // SelectReasonHandler handles the selection of rejection reasons via buttons.
@@ -247,41 +356,12 @@ func SendRejectionDMHandler(s *discordgo.Session, i *discordgo.InteractionCreate
 		return
 	}
 
-	// Create and send the DM
-	dmEmbed := &discordgo.MessageEmbed{
-		Title:       "您的投稿未通过审核",
-		Description: "很遗憾，您提交的以下安利投稿未通过审核：",
-		Color:       0xFF0000, // Red
-		Fields: []*discordgo.MessageEmbedField{
-			{
-				Name:  "您的安利标题",
-				Value: submission.RecommendTitle,
-			},
-			{
-				Name:  "不通过理由",
-				Value: "- " + strings.Join(reasons, "\n- "),
-			},
-		},
-		Footer: &discordgo.MessageEmbedFooter{
-			Text: "感谢您的参与，期待您下次的分享！",
-		},
-	}
-
-	userChannel, err := s.UserChannelCreate(submission.UserID)
-	if err != nil {
-		log.Printf("Could not create DM channel for user %s: %v", submission.UserID, err)
-		return
-	}
-	// Send the embed first
-	_, err = s.ChannelMessageSendEmbed(userChannel.ID, dmEmbed)
-	if err != nil {
-		log.Printf("Could not send DM embed to user %s: %v", submission.UserID, err)
-		// We can still try to send the plain text content
-	}
-	// Send the plain content for easy copying
-	_, err = s.ChannelMessageSend(userChannel.ID, fmt.Sprintf("```\n%s\n```", submission.RecommendContent))
-	if err != nil {
-		log.Printf("Could not send DM plain text to user %s: %v", submission.UserID, err)
+	ctx := logger.With(logger.InteractionContext(i), logger.KeySubmissionID, submissionID, logger.KeyCacheID, cacheID)
+	if err := notify.Send(ctx, notify.KindReject, submission.UserID, notify.Data{
+		Submission: submission,
+		Reasons:    reasons,
+	}); err != nil {
+		logger.FromContext(ctx).Error("could not queue rejection notification", "error", err)
 	}
 
 	// Cleanup cache and update the original message
@@ -382,50 +462,65 @@ func adminActionUpdate(s *discordgo.Session, i *discordgo.InteractionCreate, use
 	}
 }
 
-func processVoteRemoval(s *discordgo.Session, i *discordgo.InteractionCreate, submissionID, voterID, cacheID string) {
+func processVoteRemoval(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, submissionID, voterID, cacheID string) {
+	ctx = logger.With(ctx, logger.KeySubmissionID, submissionID, logger.KeyCacheID, cacheID)
+	l := logger.FromContext(ctx)
+
 	voteManager, err := vote.NewManager()
 	if err != nil {
-		log.Printf("Failed to create vote manager: %v", err)
+		l.Error("failed to create vote manager", "error", err)
 		return
 	}
 
 	submission, err := db.GetSubmission(submissionID)
 	if err != nil {
-		log.Printf("Failed to get submission %s: %v", submissionID, err)
+		l.Error("failed to get submission", "error", err)
 		return
 	}
 	if submission == nil {
-		log.Printf("Submission %s not found", submissionID)
+		l.Warn("submission not found")
 		return
 	}
 
 	session, err := voteManager.LoadSession(submission.VoteFileID)
 	if err != nil {
-		log.Printf("Failed to load vote session for submission %s (VoteFileID: %s): %v", submissionID, submission.VoteFileID, err)
+		l.Error("failed to load vote session", "vote_file_id", submission.VoteFileID, "error", err)
 		return
 	}
 	session.SubmissionID = submissionID // Keep the original submission ID for logic
 
 	if removed := session.RemoveVote(voterID); removed {
 		if err := voteManager.SaveSession(session); err != nil {
-			log.Printf("Failed to save vote session for submission %s: %v", submissionID, err)
+			l.Error("failed to save vote session", "error", err)
 			return
 		}
-		updateReviewMessage(s, i, session)
+		l.Info("vote retracted", "voter_id", voterID, "votes", len(session.Votes))
+		metrics.VotesCast.WithLabelValues("remove").Inc()
+		postToReviewThread(ctx, s, i.Message.ID, fmt.Sprintf("<@%s> 撤回了投票", voterID))
+		updateReviewMessage(ctx, s, i, session)
 		// Also re-evaluate the vote result after removal
-		processVoteResult(s, i, session, false, cacheID) // Assuming replyToOriginal is false for this action
+		processVoteResult(ctx, s, i, session, false, cacheID) // Assuming replyToOriginal is false for this action
 	}
 }
 
 // ModalBanHandler handles the submission of the ban reason modal.
 func ModalBanHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	parts := strings.Split(i.ModalSubmitData().CustomID, ":")
-	if len(parts) != 2 {
+	if len(parts) != 2 && len(parts) != 3 {
 		return // Invalid custom ID
 	}
 	cacheID := parts[1]
 	voterID := i.Member.User.ID
 	reason := i.ModalSubmitData().Components[0].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput).Value
+	var templateID int64
+	if len(parts) == 3 {
+		// Opened from the reason template menu, the text input only holds an optional addition
+		reason, templateID = composeTemplateReason(logger.With(logger.InteractionContext(i), logger.KeyCacheID, cacheID), parts[2], reason)
+	}
+	if strings.TrimSpace(reason) == "" {
+		respondEphemeral(s, i, "❌ 理由模板已被删除，请重新选择或填写理由")
+		return
+	}
 
 	// Get submission data from cache
 	cacheData, found := utils.GetFromCache(cacheID)
@@ -441,6 +536,12 @@ func ModalBanHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		return
 	}
 
+	// The claim may have been taken while the modal was open
+	if notice := claimBlocksVote(i, cacheData); notice != "" {
+		respondEphemeral(s, i, notice)
+		return
+	}
+
 	submissionID := cacheData.SubmissionID
 
 	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
@@ -451,7 +552,88 @@ func ModalBanHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		return
 	}
 
-	go processVote(s, i, submissionID, voterID, vote.Ban, reason, cacheData.ReplyToOriginal, cacheID)
+	go func() {
+		ctx := logger.InteractionContext(i)
+		if processVote(ctx, s, i, submissionID, voterID, vote.Ban, reason, cacheData.ReplyToOriginal, cacheID) {
+			recordTemplateUsage(ctx, templateID)
+		}
+	}()
+}
+
+// ModalReviseHandler handles the submission of the revision notes modal.
+func ModalReviseHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	parts := strings.Split(i.ModalSubmitData().CustomID, ":")
+	if len(parts) != 2 {
+		return // Invalid custom ID
+	}
+	cacheID := parts[1]
+	ctx := logger.With(logger.InteractionContext(i), logger.KeyCacheID, cacheID)
+	voterID := i.Member.User.ID
+	notes := i.ModalSubmitData().Components[0].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput).Value
+
+	cacheData, found := utils.GetFromCache(cacheID)
+	if !found {
+		logger.FromContext(ctx).Warn("cache data not found")
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "投票请求已过期，请联系开发者确认是否是 bot 重启导致的缓存丢失或者审核超时",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		return
+	}
+
+	// The claim may have been taken while the modal was open
+	if notice := claimBlocksVote(i, cacheData); notice != "" {
+		respondEphemeral(s, i, notice)
+		return
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to send deferred response", "error", err)
+		return
+	}
+
+	go processVote(logger.InteractionContext(i), s, i, cacheData.SubmissionID, voterID, vote.Revise, notes, cacheData.ReplyToOriginal, cacheID)
+}
+
+// ModalWarnHandler handles the submission of the warning modal.
+func ModalWarnHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	parts := strings.Split(i.ModalSubmitData().CustomID, ":")
+	if len(parts) != 2 {
+		return // Invalid custom ID
+	}
+	cacheID := parts[1]
+	ctx := logger.With(logger.InteractionContext(i), logger.KeyCacheID, cacheID)
+	voterID := i.Member.User.ID
+	reason := i.ModalSubmitData().Components[0].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput).Value
+
+	cacheData, found := utils.GetFromCache(cacheID)
+	if !found {
+		logger.FromContext(ctx).Warn("cache data not found")
+		respondEphemeral(s, i, "投票请求已过期，请联系开发者确认是否是 bot 重启导致的缓存丢失或者审核超时")
+		return
+	}
+
+	// The claim may have been taken while the modal was open
+	if notice := claimBlocksVote(i, cacheData); notice != "" {
+		respondEphemeral(s, i, notice)
+		return
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to send deferred response", "error", err)
+		return
+	}
+
+	go processVote(logger.InteractionContext(i), s, i, cacheData.SubmissionID, voterID, vote.Warn, reason, cacheData.ReplyToOriginal, cacheID)
 }
 
 // SelectBanReasonHandler handles the selection of ban reasons via buttons.
@@ -530,24 +712,14 @@ func SendBanDMHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		return
 	}
 
-	// This logic is moved from handleStatusChange
-	updatedUser, err := db.ApplyBan(submission.UserID, 3*24*time.Hour)
+	// The ban was applied by handleStatusChange when the vote was decided, only the notification is sent here
+	user, err := db.GetUserStats(submission.UserID)
 	if err != nil {
-		log.Printf("Failed to apply temporary ban to user %s: %v", submission.UserID, err)
+		log.Printf("Could not get ban status of user %s: %v", submission.UserID, err)
 		return
 	}
 
-	isPermanent := false
-	if updatedUser.BanCount >= 3 {
-		err := db.ApplyPermanentBan(submission.UserID)
-		if err != nil {
-			log.Printf("Failed to apply permanent ban to user %s: %v", submission.UserID, err)
-		} else {
-			isPermanent = true
-		}
-	}
-
-	sendBanNotification(s, submission.UserID, isPermanent, updatedUser.BanCount, selectedReason)
+	sendBanNotification(logger.With(logger.InteractionContext(i), logger.KeySubmissionID, submissionID), submission, user.IsPermanentlyBanned, user.BanCount, selectedReason)
 
 	// Cleanup cache and update the original message
 	utils.DeleteBanReasons(submissionID)
@@ -2,6 +2,8 @@ package amway
 
 import (
 	"amway/db"
+	"amway/logger"
+	"amway/metrics"
 	"amway/model"
 	"amway/utils"
 	"fmt"
@@ -267,21 +269,7 @@ func ContentSubmissionHandler(s *discordgo.Session, i *discordgo.InteractionCrea
 		return
 	}
 
-	var recommendTitle, recommendContent string
-	for _, component := range data.Components {
-		if actionRow, ok := component.(*discordgo.ActionsRow); ok {
-			for _, comp := range actionRow.Components {
-				if textInput, ok := comp.(*discordgo.TextInput); ok {
-					switch textInput.CustomID {
-					case "recommend_title":
-						recommendTitle = textInput.Value
-					case "recommend_content":
-						recommendContent = textInput.Value
-					}
-				}
-			}
-		}
-	}
+	recommendTitle, recommendContent := readSubmissionContentInputs(data)
 
 	if recommendTitle == "" || recommendContent == "" {
 		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
@@ -313,6 +301,25 @@ func ContentSubmissionHandler(s *discordgo.Session, i *discordgo.InteractionCrea
 	}
 }
 
+// readSubmissionContentInputs reads the title and content fields of the submission content modal.
+func readSubmissionContentInputs(data discordgo.ModalSubmitInteractionData) (recommendTitle, recommendContent string) {
+	for _, component := range data.Components {
+		if actionRow, ok := component.(*discordgo.ActionsRow); ok {
+			for _, comp := range actionRow.Components {
+				if textInput, ok := comp.(*discordgo.TextInput); ok {
+					switch textInput.CustomID {
+					case "recommend_title":
+						recommendTitle = textInput.Value
+					case "recommend_content":
+						recommendContent = textInput.Value
+					}
+				}
+			}
+		}
+	}
+	return recommendTitle, recommendContent
+}
+
 func FinalSubmissionHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	if !validateUserBanStatus(s, i) {
 		return
@@ -332,6 +339,9 @@ func FinalSubmissionHandler(s *discordgo.Session, i *discordgo.InteractionCreate
 	isAnonymousStr := parts[2]
 	isAnonymous := isAnonymousStr == "true"
 
+	ctx := logger.With(logger.InteractionContext(i), logger.KeyCacheID, cacheID)
+	l := logger.FromContext(ctx)
+
 	cacheData, ok := validateCacheData(s, i, cacheID)
 	if !ok {
 		return
@@ -343,7 +353,7 @@ func FinalSubmissionHandler(s *discordgo.Session, i *discordgo.InteractionCreate
 		Data: BuildFinalSuccessResponseData(),
 	})
 	if err != nil {
-		fmt.Printf("Error updating final submission message: %v\n", err)
+		l.Error("error updating final submission message", "error", err)
 	}
 
 	guildID := i.GuildID
@@ -355,21 +365,25 @@ func FinalSubmissionHandler(s *discordgo.Session, i *discordgo.InteractionCreate
 		originalPostTimestamp = postInfo.Timestamp
 		originalTitle = postInfo.Title
 	} else {
-		fmt.Printf("二次验证帖子以获取元数据时出错: %v\n", err)
+		l.Warn("二次验证帖子以获取元数据时出错", "url", originalURL, "error", err)
 	}
 
 	submissionID, err := db.AddSubmissionV2(
 		i.Member.User.ID, originalURL,
 		cacheData.RecommendTitle, cacheData.RecommendContent,
-		originalTitle, cacheData.OriginalAuthor, originalPostTimestamp, guildID, i.Member.User.Username, isAnonymous,
+		originalTitle, cacheData.OriginalAuthor, originalPostTimestamp, guildID, i.Member.User.Username, isAnonymous, cacheData.ReplyToOriginal,
 	)
 	if err != nil {
-		fmt.Printf("Error adding submission to database: %v\n", err)
+		l.Error("error adding submission to database", "error", err)
 		errorContent := fmt.Sprintf("提交失败，请稍后再试错误详情: %v", err)
 		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: &errorContent})
 		return
 	}
 
+	ctx = logger.With(ctx, logger.KeySubmissionID, submissionID)
+	logger.FromContext(ctx).Info("submission created", "anonymous", isAnonymous)
+	metrics.SubmissionsCreated.Inc()
+
 	// Record submission time for rate limiting
 	utils.RecordSubmissionTime(i.Member.User.ID)
 
@@ -385,8 +399,18 @@ func FinalSubmissionHandler(s *discordgo.Session, i *discordgo.InteractionCreate
 		RecommendContent: cacheData.RecommendContent,
 		OriginalAuthor:   cacheData.OriginalAuthor,
 		IsAnonymous:      isAnonymous,
+		GuildID:          guildID,
+		Status:           "pending",
+	}
+
+	// Trusted authors skip review, except for a random sample
+	if shouldAutoApprove(computeTrustLevel(ctx, submission.UserID)) {
+		logger.FromContext(ctx).Info("submission auto-approved for trusted author")
+		utils.RemoveFromCache(cacheID)
+		autoApproveSubmission(ctx, s, submission, cacheData.ReplyToOriginal)
+		return
 	}
-	SendSubmissionToReviewChannel(s, submission, cacheID)
+	SendSubmissionToReviewChannel(ctx, s, submission, cacheID)
 }
 
 func EditSubmissionContentHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
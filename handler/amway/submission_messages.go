@@ -4,30 +4,50 @@ import (
 	"amway/config"
 	"amway/db"
 	"amway/handler/tools"
+	"amway/logger"
 	"amway/model"
+	"amway/outbox"
 	"amway/utils"
 	"amway/vote"
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
 
-// BuildVoteStatusEmbed builds the embed for the current voting status.
-func BuildVoteStatusEmbed(session *vote.Session) *discordgo.MessageEmbed {
+// buildVoteSummary lists each vote together with the reason given for it.
+func buildVoteSummary(votes []vote.Vote) string {
 	var voteSummary string
-	for _, v := range session.Votes {
-		if (v.Type == vote.Reject || v.Type == vote.Ban) && v.Reason != "" {
+	for _, v := range votes {
+		if (v.Type == vote.Reject || v.Type == vote.Ban || v.Type == vote.Revise || v.Type == vote.Warn) && v.Reason != "" {
 			voteSummary += fmt.Sprintf("<@%s>投了 `%s`\n> 理由: %s\n", v.VoterID, v.Type, v.Reason)
 		} else {
 			voteSummary += fmt.Sprintf("<@%s>投了 `%s`\n", v.VoterID, v.Type)
 		}
 	}
+	return voteSummary
+}
+
+// BuildVoteStatusEmbed builds the embed for the current voting status.
+// In blind mode only the number of votes is shown so later reviewers are not anchored by earlier votes.
+func BuildVoteStatusEmbed(session *vote.Session, blind bool) *discordgo.MessageEmbed {
+	if blind {
+		return &discordgo.MessageEmbed{
+			Title:       "当前投票状态",
+			Description: fmt.Sprintf("已投 %d 票", len(session.Votes)),
+			Color:       0x00BFFF, // Deep sky blue
+			Footer: &discordgo.MessageEmbedFooter{
+				Text: "盲审模式：投票结束后公开全部投票，点击「我的投票」查看自己的投票",
+			},
+		}
+	}
 
 	voteEmbed := &discordgo.MessageEmbed{
 		Title:       "当前投票状态",
-		Description: voteSummary,
+		Description: buildVoteSummary(session.Votes),
 		Color:       0x00BFFF, // Deep sky blue
 	}
 
@@ -52,12 +72,31 @@ func BuildVoteStatusEmbed(session *vote.Session) *discordgo.MessageEmbed {
 }
 
 // BuildFinalVoteEmbed builds the embed for the completed vote.
-func BuildFinalVoteEmbed(submissionID, finalStatus string) *discordgo.MessageEmbed {
-	return &discordgo.MessageEmbed{
+// Blind votes are revealed here, since the status embed never showed them, and so are
+// the votes that were changed or retracted during the round.
+func BuildFinalVoteEmbed(session *vote.Session, finalStatus string, blind bool) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
 		Title:       "✅ 投票结束",
-		Description: fmt.Sprintf("对投稿 `%s` 的投票已完成\n\n**最终结果:** `%s`", submissionID, finalStatus),
+		Description: fmt.Sprintf("对投稿 `%s` 的投票已完成\n\n**最终结果:** `%s`", session.SubmissionID, finalStatus),
 		Color:       0x5865F2, // Discord Blurple
 	}
+	if blind && len(session.Votes) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "投票明细",
+			Value: truncateField(buildVoteSummary(session.Votes)),
+		})
+	}
+	if changed := tools.ChangedVoteEvents(session.RoundHistory()); len(changed) > 0 {
+		var lines []string
+		for _, e := range changed {
+			lines = append(lines, tools.FormatVoteEvent(e))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "改票记录",
+			Value: truncateField(strings.Join(lines, "\n")),
+		})
+	}
+	return embed
 }
 
 // BuildRejectionComponents builds the buttons for sending rejection reasons.
@@ -65,9 +104,10 @@ func BuildRejectionComponents(cacheID string, reasons []string) []discordgo.Mess
 	var components []discordgo.MessageComponent
 	if len(reasons) > 0 {
 		reasonButtons := []discordgo.MessageComponent{}
-		for idx := range reasons {
+		for idx, reason := range reasons {
+			// Show the start of the reason so reviewers can tell template reasons apart
 			reasonButtons = append(reasonButtons, discordgo.Button{
-				Label:    fmt.Sprintf("理由%d", idx+1),
+				Label:    truncateRunes(fmt.Sprintf("理由%d：%s", idx+1, reason), 24),
 				Style:    discordgo.SecondaryButton,
 				CustomID: fmt.Sprintf("select_reason:%s:%d", cacheID, idx),
 			})
@@ -104,10 +144,7 @@ func BuildBanComponents(cacheID string, reasons []string) []discordgo.MessageCom
 		var reasonButtons []discordgo.MessageComponent
 		for idx, reason := range reasons {
 			// Truncate reason for button label if it's too long
-			label := reason
-			if len(label) > 20 {
-				label = label[:17] + "..."
-			}
+			label := truncateRunes(reason, 20)
 			reasonButtons = append(reasonButtons, discordgo.Button{
 				Label:    label,
 				Style:    discordgo.SecondaryButton,
@@ -252,54 +289,80 @@ func BuildNotificationMessage(submission *model.Submission, publishMsg *discordg
 }
 
 // PublishSubmission handles the entire process of publishing an approved or featured submission.
-func PublishSubmission(s *discordgo.Session, submission *model.Submission, replyToOriginal bool) {
+// Once the publication succeeds, the reply to the original post is attempted and the role grant
+// and author notification are queued through the outbox.
+func PublishSubmission(ctx context.Context, s *discordgo.Session, submission *model.Submission, replyToOriginal bool) (*discordgo.Message, error) {
 	publicationMessage, err := BuildPublicationMessage(submission)
 	if err != nil {
-		log.Printf("Error building publication message for submission %s: %v", submission.ID, err)
-		return
+		return nil, fmt.Errorf("error building publication message for submission %s: %w", submission.ID, err)
 	}
 
 	publishMsg, err := s.ChannelMessageSendComplex(config.Cfg.AmwayBot.Amway.PublishChannelID, publicationMessage)
 	if err != nil {
-		log.Printf("Error sending publication message for submission %s: %v", submission.ID, err)
-		return
+		return nil, fmt.Errorf("error sending publication message for submission %s: %w", submission.ID, err)
 	}
 
+	l := logger.FromContext(ctx)
+
+	// Record the message ID before anything else so a retry never publishes twice
+	if err := db.UpdateFinalAmwayMessageID(submission.ID, publishMsg.ID); err != nil {
+		l.Error("error updating final amway message ID", "error", err)
+	}
+	l.Info("submission published", "channel_id", publishMsg.ChannelID, "message_id", publishMsg.ID)
+
 	// Add standard reactions to the published message
 	s.MessageReactionAdd(publishMsg.ChannelID, publishMsg.ID, "👍")
 	s.MessageReactionAdd(publishMsg.ChannelID, publishMsg.ID, "🤔")
 	s.MessageReactionAdd(publishMsg.ChannelID, publishMsg.ID, "🚫")
 
-	if err := db.UpdateFinalAmwayMessageID(submission.ID, publishMsg.ID); err != nil {
-		log.Printf("Error updating final amway message ID for submission %s: %v", submission.ID, err)
+	if replyToOriginal {
+		// Try the reply right away so the author's notification can link to it,
+		// and leave retries to the outbox.
+		if err := sendNotificationToOriginalPost(ctx, s, submission, publishMsg); err != nil && !errors.Is(err, outbox.ErrPermanent) {
+			l.Warn("thread notification failed, queueing retry", "error", err)
+			err := outbox.Enqueue(outbox.ActionNotifyThread, outbox.NotifyThreadPayload{
+				SubmissionID:     submission.ID,
+				PublishChannelID: publishMsg.ChannelID,
+				PublishMessageID: publishMsg.ID,
+				InteractionID:    logger.InteractionID(ctx),
+			})
+			if err != nil {
+				l.Error("error queueing thread notification", "error", err)
+			}
+		}
 	}
 
-	if replyToOriginal {
-		sendNotificationToOriginalPost(s, submission, publishMsg)
+	// The role grant and the author's notification need the published message, so they run afterwards
+	if err := outbox.Enqueue(outbox.ActionAnnounceApproval, outbox.AnnounceApprovalPayload{
+		SubmissionID:  submission.ID,
+		InteractionID: logger.InteractionID(ctx),
+	}); err != nil {
+		l.Error("error queueing approval announcement", "error", err)
 	}
+
+	return publishMsg, nil
 }
 
 // sendNotificationToOriginalPost sends a notification to the original post about the submission.
-func sendNotificationToOriginalPost(s *discordgo.Session, submission *model.Submission, publishMsg *discordgo.Message) {
+func sendNotificationToOriginalPost(ctx context.Context, s *discordgo.Session, submission *model.Submission, publishMsg *discordgo.Message) error {
 	originalChannelID, notification, err := BuildNotificationMessage(submission, publishMsg)
 	if err != nil {
-		log.Printf("Error building notification message for submission %s: %v", submission.ID, err)
-		return
+		return outbox.Permanent(err)
 	}
 
 	msg, err := s.ChannelMessageSendComplex(originalChannelID, notification)
 	if err != nil {
-		if restErr, ok := err.(*discordgo.RESTError); ok && restErr.Message != nil && restErr.Message.Code == 30033 {
-			log.Printf("Skipping notification for submission %s: thread participants limit reached.", submission.ID)
-		} else {
-			log.Printf("Error sending notification to original post for submission %s: %v", submission.ID, err)
+		if outbox.IsThreadMemberLimit(err) {
+			logger.FromContext(ctx).Warn("skipping notification: thread participants limit reached")
+			return nil
 		}
-		return
+		return fmt.Errorf("error sending notification to original post for submission %s: %w", submission.ID, err)
 	}
 
 	if err := db.UpdateThreadMessageID(submission.ID, msg.ID); err != nil {
-		log.Printf("Error updating thread message ID for submission %s: %v", submission.ID, err)
+		logger.FromContext(ctx).Error("error updating thread message ID", "error", err)
 	}
+	return nil
 }
 
 // UpdateNotificationInOriginalPost updates an existing notification message in the original post.
@@ -3,9 +3,12 @@ package amway
 import (
 	"amway/config"
 	"amway/db"
+	"amway/metrics"
 	"amway/model"
+	"amway/outbox"
 	"amway/utils"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -28,6 +31,8 @@ func MessageReactionRemove(s *discordgo.Session, r *discordgo.MessageReactionRem
 }
 
 func handleReactionUpdate(s *discordgo.Session, channelID, messageID, userID, emojiName, action string) {
+	metrics.ReactionEvents.WithLabelValues(emojiName, strings.ToLower(action)).Inc()
+
 	submission, err := db.GetSubmissionByMessageID(messageID)
 	if err != nil {
 		log.Printf("Error getting submission by message ID %s: %v", messageID, err)
@@ -138,15 +143,15 @@ func checkAndDeleteSubmission(s *discordgo.Session, submissionID, channelID, mes
 		}
 
 		// 使用传入的 ID 删除主要的 amway 消息
-		if err := s.ChannelMessageDelete(channelID, messageID); err != nil {
-			log.Printf("Failed to delete amway message %s in channel %s: %v", messageID, channelID, err)
+		if err := outbox.Enqueue(outbox.ActionDeleteMessage, outbox.DeleteMessagePayload{ChannelID: channelID, MessageID: messageID}); err != nil {
+			log.Printf("Failed to queue deletion of amway message %s in channel %s: %v", messageID, channelID, err)
 		}
 
 		// 如果存在原始帖子，也删除转发的消息
 		if submission.ThreadMessageID != "0" && submission.ThreadMessageID != "" {
 			if originalChannelID, _, err := utils.GetOriginalPostDetails(submission.URL); err == nil {
-				if err := s.ChannelMessageDelete(originalChannelID, submission.ThreadMessageID); err != nil {
-					log.Printf("Failed to delete forwarded message %s in channel %s: %v", submission.ThreadMessageID, originalChannelID, err)
+				if err := outbox.Enqueue(outbox.ActionDeleteMessage, outbox.DeleteMessagePayload{ChannelID: originalChannelID, MessageID: submission.ThreadMessageID}); err != nil {
+					log.Printf("Failed to queue deletion of forwarded message %s in channel %s: %v", submission.ThreadMessageID, originalChannelID, err)
 				}
 			} else {
 				log.Printf("Failed to parse original post details from URL %s: %v", submission.URL, err)
@@ -2,19 +2,24 @@ package amway
 
 import (
 	"amway/config"
+	"amway/db"
+	"amway/logger"
 	"amway/model"
+	"amway/utils"
+	"context"
 	"fmt"
-	"log"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
 )
 
 // SendSubmissionToReviewChannel sends a submission to the review channel with appropriate formatting.
-func SendSubmissionToReviewChannel(s *discordgo.Session, submission *model.Submission, cacheID string) {
+func SendSubmissionToReviewChannel(ctx context.Context, s *discordgo.Session, submission *model.Submission, cacheID string) error {
+	l := logger.FromContext(ctx)
 	reviewChannelID := config.Cfg.AmwayBot.Amway.ReviewChannelID
 	if reviewChannelID == "" {
-		log.Printf("Review channel ID not configured")
-		return
+		l.Error("review channel ID not configured")
+		return fmt.Errorf("review channel ID not configured")
 	}
 
 	var embed *discordgo.MessageEmbed
@@ -45,6 +50,77 @@ func SendSubmissionToReviewChannel(s *discordgo.Session, submission *model.Submi
 		}
 	}
 
+	if notes := previousRevisionNotes(submission); len(notes) > 0 {
+		embed.Title += "（修改后重新提交）"
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "上一轮修改意见",
+			Value: truncateField("- " + strings.Join(notes, "\n- ")),
+		})
+	}
+
+	if label, ok := trustLevelLabels[computeTrustLevel(ctx, submission.UserID)]; ok {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "投稿人信任等级",
+			Value:  label,
+			Inline: true,
+		})
+	}
+
+	applyReviewerNotesField(ctx, embed, submission.UserID)
+
+	if data, ok := utils.GetFromCache(cacheID); ok {
+		embed.Fields = append(embed.Fields, buildDeadlineField(utils.SubmissionDeadline(data)))
+	}
+
+	// Spread the review work by assigning the submission to reviewers from the pool
+	var assignees []string
+	if assignmentEnabled() {
+		assignees = pickReviewers(ctx, submission, assigneesPerSubmission(), nil)
+		applyAssignmentField(embed, assignees)
+	}
+
+	controls := []discordgo.MessageComponent{
+		discordgo.Button{
+			Label:    "认领",
+			Style:    discordgo.SecondaryButton,
+			CustomID: "claim:" + cacheID,
+			Emoji:    &discordgo.ComponentEmoji{Name: "🔒"},
+		},
+		discordgo.Button{
+			Label:    "需修改",
+			Style:    discordgo.SecondaryButton,
+			CustomID: "vote:revise:" + cacheID,
+			Emoji:    &discordgo.ComponentEmoji{Name: "✏️"},
+		},
+		discordgo.Button{
+			Label:    "警告",
+			Style:    discordgo.SecondaryButton,
+			CustomID: "vote:warn:" + cacheID,
+			Emoji:    &discordgo.ComponentEmoji{Name: "⚠️"},
+		},
+		discordgo.Button{
+			Label:    "延长期限",
+			Style:    discordgo.SecondaryButton,
+			CustomID: "extend_deadline:" + cacheID,
+			Emoji:    &discordgo.ComponentEmoji{Name: "⏳"},
+		},
+		discordgo.Button{
+			Label:    "备注",
+			Style:    discordgo.SecondaryButton,
+			CustomID: "reviewer_note:" + submission.ID,
+			Emoji:    &discordgo.ComponentEmoji{Name: "📝"},
+		},
+	}
+	if isBlindVoting(submission.GuildID) {
+		// Votes are hidden under blind voting, so reviewers check their own vote privately
+		controls = append(controls, discordgo.Button{
+			Label:    "我的投票",
+			Style:    discordgo.SecondaryButton,
+			CustomID: "my_vote:" + cacheID,
+			Emoji:    &discordgo.ComponentEmoji{Name: "👁️"},
+		})
+	}
+
 	components := []discordgo.MessageComponent{
 		discordgo.ActionsRow{
 			Components: []discordgo.MessageComponent{
@@ -81,13 +157,40 @@ func SendSubmissionToReviewChannel(s *discordgo.Session, submission *model.Submi
 			},
 		},
 	}
+	// Discord allows five buttons per row, the controls wrap onto further rows
+	for len(controls) > 0 {
+		n := min(len(controls), 5)
+		components = append(components, discordgo.ActionsRow{Components: controls[:n]})
+		controls = controls[n:]
+	}
+
+	if row := buildReasonTemplateRow(ctx, submission.GuildID, cacheID); row != nil {
+		components = append(components, *row)
+	}
 
-	_, err := s.ChannelMessageSendComplex(reviewChannelID, &discordgo.MessageSend{
+	send := &discordgo.MessageSend{
 		Embed:      embed,
 		Components: components,
-	})
+	}
+	if len(assignees) > 0 {
+		send.Content = mentionList(assignees) + " 请审核这条投稿"
+		send.AllowedMentions = &discordgo.MessageAllowedMentions{Users: assignees}
+	}
+	msg, err := s.ChannelMessageSendComplex(reviewChannelID, send)
 
 	if err != nil {
-		fmt.Printf("Error sending review message: %v\n", err)
+		l.Error("error sending review message", "error", err)
+		return err
+	}
+	l.Debug("review message sent", "channel_id", reviewChannelID, "message_id", msg.ID)
+
+	// Give every submission its own thread so discussions about different submissions do not interleave
+	startReviewThread(ctx, s, reviewChannelID, msg.ID, submission)
+	recordAssignments(ctx, submission.ID, assignees)
+
+	// Remember where the review message lives so the review queue can link to it
+	if err := db.UpdateReviewMessageID(submission.ID, msg.ID); err != nil {
+		l.Error("error updating review message ID", "error", err)
 	}
+	return nil
 }
@@ -0,0 +1,124 @@
+package amway
+
+import (
+	"amway/config"
+	"amway/db"
+	"amway/logger"
+	"amway/model"
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// trustLevel ranks authors by their submission record.
+type trustLevel int
+
+const (
+	trustNone trustLevel = iota
+	trustMid
+	trustHigh
+)
+
+// trustLevelLabels describes each trust level on review messages and in the queue.
+var trustLevelLabels = map[trustLevel]string{
+	trustMid:  "🔸 中等信任",
+	trustHigh: "⭐ 高信任",
+}
+
+// computeTrustLevel derives an author's trust level from their stats and account age.
+// Banned authors never receive trust.
+func computeTrustLevel(ctx context.Context, userID string) trustLevel {
+	l := logger.FromContext(ctx)
+	user, err := db.GetUserStats(userID)
+	if err != nil {
+		l.Error("failed to load stats for trust level", "target_user_id", userID, "error", err)
+		return trustNone
+	}
+	if user.IsPermanentlyBanned || (user.BannedUntil.Valid && user.BannedUntil.Int64 > time.Now().Unix()) {
+		return trustNone
+	}
+	approved, err := db.CountApprovedSubmissions(userID)
+	if err != nil {
+		l.Error("failed to count approved submissions", "target_user_id", userID, "error", err)
+		return trustNone
+	}
+	var accountAge time.Duration
+	if created, err := discordgo.SnowflakeTimestamp(userID); err == nil {
+		accountAge = time.Since(created)
+	}
+
+	cfg := config.Cfg.Trust
+	switch {
+	case meetsTrustThreshold(ctx, cfg.High, user, approved, accountAge):
+		return trustHigh
+	case meetsTrustThreshold(ctx, cfg.Mid, user, approved, accountAge):
+		return trustMid
+	default:
+		return trustNone
+	}
+}
+
+// meetsTrustThreshold reports whether an author satisfies every condition of a threshold.
+func meetsTrustThreshold(ctx context.Context, t model.TrustThreshold, user *model.User, approved int, accountAge time.Duration) bool {
+	if t.MinApproved <= 0 {
+		return false // The level is disabled
+	}
+	if approved < t.MinApproved || user.FeaturedCount < t.MinFeatured || user.BanCount > t.MaxBans {
+		return false
+	}
+	if t.MaxRejectRate > 0 {
+		// Bans are counted as rejections as well
+		reviewed := approved + user.RejectedCount
+		if reviewed > 0 && float64(user.RejectedCount)/float64(reviewed) > t.MaxRejectRate {
+			return false
+		}
+	}
+	if t.MinAccountAge != "" {
+		minAge, err := time.ParseDuration(t.MinAccountAge)
+		if err != nil {
+			logger.FromContext(ctx).Error("invalid min_account_age in trust config", "min_account_age", t.MinAccountAge, "error", err)
+			return false
+		}
+		if accountAge < minAge {
+			return false
+		}
+	}
+	return true
+}
+
+// shouldAutoApprove decides whether a high trust submission skips review.
+// A configurable share is still sampled into review.
+func shouldAutoApprove(level trustLevel) bool {
+	cfg := config.Cfg.Trust
+	if level != trustHigh || !cfg.AutoApprove {
+		return false
+	}
+	return rand.Float64() >= cfg.ReviewSampleRate
+}
+
+// autoApproveSubmission publishes a high trust submission without review and leaves a notice in the review channel.
+func autoApproveSubmission(ctx context.Context, s *discordgo.Session, submission *model.Submission, replyToOriginal bool) {
+	handleStatusChange(ctx, s, submission, "approved", "", replyToOriginal, "")
+
+	reviewChannelID := config.Cfg.AmwayBot.Amway.ReviewChannelID
+	if reviewChannelID == "" {
+		return
+	}
+	_, err := s.ChannelMessageSendComplex(reviewChannelID, &discordgo.MessageSend{
+		Embed: &discordgo.MessageEmbed{
+			Title:       "投稿已自动通过",
+			Description: fmt.Sprintf("**投稿ID:** %s\n**投稿人:** <@%s>\n**安利标题:** %s\n**原帖链接:** %s", submission.ID, submission.UserID, submission.RecommendTitle, submission.URL),
+			Color:       0x00FF00, // Green for approved
+			Footer: &discordgo.MessageEmbedFooter{
+				Text: fmt.Sprintf("投稿人为高信任用户，如有问题可使用 /amway_admin 重新审核 • ID: %s", submission.ID),
+			},
+		},
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to post auto-approval notice", "error", err)
+	}
+}
@@ -5,39 +5,44 @@ package amway
 import (
 	"amway/db"
 	"amway/handler/tools"
+	"amway/logger"
+	"amway/metrics"
 	"amway/model"
-	"amway/shared"
+	"amway/notify"
+	"amway/outbox"
 	"amway/utils"
 	"amway/vote"
-	"fmt"
-	"log"
+	"context"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
 
-// processVote is the core logic for handling a vote submission.
-func processVote(s *discordgo.Session, i *discordgo.InteractionCreate, submissionID, voterID string, voteType vote.VoteType, reason string, replyToOriginal bool, cacheID string) {
+// processVote is the core logic for handling a vote submission. It reports whether the vote was recorded.
+func processVote(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, submissionID, voterID string, voteType vote.VoteType, reason string, replyToOriginal bool, cacheID string) bool {
+	ctx = logger.With(ctx, logger.KeySubmissionID, submissionID, logger.KeyCacheID, cacheID)
+	l := logger.FromContext(ctx)
+
 	voteManager, err := vote.NewManager()
 	if err != nil {
-		log.Printf("Failed to create vote manager: %v", err)
-		return
+		l.Error("failed to create vote manager", "error", err)
+		return false
 	}
 
 	submission, err := db.GetSubmission(submissionID)
 	if err != nil {
-		log.Printf("Failed to get submission %s: %v", submissionID, err)
-		return
+		l.Error("failed to get submission", "error", err)
+		return false
 	}
 	if submission == nil {
-		log.Printf("Submission %s not found", submissionID)
-		return
+		l.Warn("submission not found")
+		return false
 	}
 
 	session, err := voteManager.LoadSession(submission.VoteFileID)
 	if err != nil {
-		log.Printf("Failed to load vote session for submission %s (VoteFileID: %s): %v", submissionID, submission.VoteFileID, err)
-		return
+		l.Error("failed to load vote session", "vote_file_id", submission.VoteFileID, "error", err)
+		return false
 	}
 	session.SubmissionID = submissionID // Keep the original submission ID for logic
 
@@ -50,17 +55,29 @@ func processVote(s *discordgo.Session, i *discordgo.InteractionCreate, submissio
 	session.AddVote(newVote)
 
 	if err := voteManager.SaveSession(session); err != nil {
-		log.Printf("Failed to save vote session for submission %s: %v", submissionID, err)
-		return
+		l.Error("failed to save vote session", "error", err)
+		return false
 	}
+	l.Info("vote recorded", "voter_id", voterID, "vote_type", voteType, "votes", len(session.Votes))
+	metrics.VotesCast.WithLabelValues(string(voteType)).Inc()
+	completeAssignment(ctx, submissionID, voterID)
+
+	mirrorVoteToThread(ctx, s, i.Message.ID, newVote, isBlindVoting(i.GuildID))
 
-	updateReviewMessage(s, i, session)
-	processVoteResult(s, i, session, replyToOriginal, cacheID)
+	// Voting ends the claim, so the next reviewer can pick the submission up
+	if utils.ReleaseClaim(cacheID, voterID) && len(i.Message.Embeds) > 0 {
+		if data, ok := utils.GetFromCache(cacheID); ok {
+			applyClaimField(i.Message.Embeds[0], data)
+		}
+	}
+	updateReviewMessage(ctx, s, i, session)
+	processVoteResult(ctx, s, i, session, replyToOriginal, cacheID)
+	return true
 }
 
 // updateReviewMessage updates the review message with the current voting status.
-func updateReviewMessage(s *discordgo.Session, i *discordgo.InteractionCreate, session *vote.Session) {
-	voteEmbed := BuildVoteStatusEmbed(session)
+func updateReviewMessage(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, session *vote.Session) {
+	voteEmbed := BuildVoteStatusEmbed(session, isBlindVoting(i.GuildID))
 
 	originalEmbeds := i.Message.Embeds
 	var updatedEmbeds []*discordgo.MessageEmbed
@@ -83,18 +100,19 @@ func updateReviewMessage(s *discordgo.Session, i *discordgo.InteractionCreate, s
 		Embeds: &updatedEmbeds,
 	})
 	if err != nil {
-		log.Printf("Failed to update review message for submission %s: %v", session.SubmissionID, err)
+		logger.FromContext(ctx).Error("failed to update review message", "error", err)
 	}
 }
 
-// processVoteResult checks the votes and takes final action if needed.
-func processVoteResult(s *discordgo.Session, i *discordgo.InteractionCreate, session *vote.Session, replyToOriginal bool, cacheID string) {
-	if len(session.Votes) < 2 {
-		return // Not enough votes to make a decision yet
+// decideOutcome applies the voting rules to a round of votes and returns the final status,
+// or an empty status while no decision has been reached. reviewerID is the voter who decided it.
+func decideOutcome(votes []vote.Vote) (finalStatus, reviewerID string) {
+	if len(votes) < 2 {
+		return "", "" // Not enough votes to make a decision yet
 	}
 
 	voteCounts := make(map[vote.VoteType]int)
-	for _, v := range session.Votes {
+	for _, v := range votes {
 		voteCounts[v.Type]++
 		// Feature vote also counts as a Pass vote
 		if v.Type == vote.Feature {
@@ -102,13 +120,10 @@ func processVoteResult(s *discordgo.Session, i *discordgo.InteractionCreate, ses
 		}
 	}
 
-	var finalStatus string
-	var reviewerID string // For now, we'll just log the last voter.
-
 	// Check for a two-vote consensus
 	for voteType, count := range voteCounts {
 		if count >= 2 {
-			reviewerID = session.Votes[len(session.Votes)-1].VoterID
+			reviewerID = votes[len(votes)-1].VoterID
 			switch voteType {
 			case vote.Pass:
 				// If we have 2 or more feature votes, the final status is "featured"
@@ -121,20 +136,24 @@ func processVoteResult(s *discordgo.Session, i *discordgo.InteractionCreate, ses
 				finalStatus = "rejected"
 			case vote.Ban:
 				finalStatus = "banned" // We'll handle the actual ban action below
+			case vote.Revise:
+				finalStatus = "revision"
+			case vote.Warn:
+				finalStatus = "warned"
 			}
 			break // A decision has been reached
 		}
 	}
 
 	// If there are 2 votes with no consensus, wait for a 3rd.
-	if len(session.Votes) == 2 && !tools.HasConsensus(voteCounts) {
-		return // Wait for the third vote
+	if len(votes) == 2 && !tools.HasConsensus(voteCounts) {
+		return "", "" // Wait for the third vote
 	}
 
-	if len(session.Votes) >= 3 {
+	if len(votes) >= 3 {
 		// With 3 or more votes, the last vote is the tie-breaker.
-		lastVoteType := session.Votes[len(session.Votes)-1].Type
-		reviewerID = session.Votes[len(session.Votes)-1].VoterID
+		lastVoteType := votes[len(votes)-1].Type
+		reviewerID = votes[len(votes)-1].VoterID
 
 		switch lastVoteType {
 		case vote.Pass:
@@ -145,18 +164,28 @@ func processVoteResult(s *discordgo.Session, i *discordgo.InteractionCreate, ses
 			finalStatus = "rejected"
 		case vote.Ban:
 			finalStatus = "banned"
+		case vote.Revise:
+			finalStatus = "revision"
+		case vote.Warn:
+			finalStatus = "warned"
 		}
 	}
+	return finalStatus, reviewerID
+}
 
+// processVoteResult checks the votes and takes final action if needed.
+func processVoteResult(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, session *vote.Session, replyToOriginal bool, cacheID string) {
+	finalStatus, reviewerID := decideOutcome(session.Votes)
 	if finalStatus == "" {
 		return // No decision reached yet
 	}
 
 	submission, err := db.GetSubmission(session.SubmissionID)
 	if err != nil {
-		log.Printf("Could not get submission %s for final processing: %v", session.SubmissionID, err)
+		logger.FromContext(ctx).Error("could not get submission for final processing", "error", err)
 		return
 	}
+	logger.FromContext(ctx).Info("vote decision reached", "old_status", submission.Status, "final_status", finalStatus, "reviewer_id", reviewerID)
 
 	oldStatus := submission.Status
 	// Only proceed if the final status is different from the old status
@@ -178,17 +207,51 @@ func processVoteResult(s *discordgo.Session, i *discordgo.InteractionCreate, ses
 		}
 	}
 
+	var revisionNotes []string
+	if finalStatus == "revision" {
+		for _, v := range session.Votes {
+			if v.Type == vote.Revise && v.Reason != "" {
+				revisionNotes = append(revisionNotes, v.Reason)
+			}
+		}
+	}
+
+	var warnReasons []string
+	if finalStatus == "warned" {
+		for _, v := range session.Votes {
+			if v.Type == vote.Warn && v.Reason != "" {
+				warnReasons = append(warnReasons, v.Reason)
+			}
+		}
+	}
+
 	if finalStatus != oldStatus {
+		metrics.VoteDecisions.WithLabelValues(finalStatus).Inc()
+		if oldStatus == "pending" && submission.Timestamp > 0 {
+			metrics.ReviewDuration.Observe(time.Since(time.Unix(submission.Timestamp, 0)).Seconds())
+		}
+
 		// For bans, we now handle the notification logic after an admin selects a reason.
 		// So, we pass an empty reason here. The actual ban is still applied.
-		handleStatusChange(s, submission, finalStatus, reviewerID, replyToOriginal, "")
+		handleStatusChange(ctx, s, submission, finalStatus, reviewerID, replyToOriginal, "")
+
+		// The author is asked to revise right away, there is no reason selection step as for rejections
+		if finalStatus == "revision" {
+			sendRevisionNotification(ctx, submission, revisionNotes, replyToOriginal)
+		}
+		// Warnings are recorded and sent right away as well, escalating to a ban under the guild's policy
+		if finalStatus == "warned" {
+			issueWarning(ctx, submission, warnReasons, reviewerID)
+		}
 	}
 
-	finalizeReviewMessage(s, i, session.SubmissionID, finalStatus, rejectionReasons, banReasons, cacheID)
+	finalizeReviewMessage(ctx, s, i, submission, session, finalStatus, rejectionReasons, banReasons, cacheID)
 }
 
 // handleStatusChange processes the consequences of a submission's final status.
-func handleStatusChange(s *discordgo.Session, submission *model.Submission, finalStatus, reviewerID string, replyToOriginal bool, selectedBanReason string) {
+func handleStatusChange(ctx context.Context, s *discordgo.Session, submission *model.Submission, finalStatus, reviewerID string, replyToOriginal bool, selectedBanReason string) {
+	l := logger.FromContext(ctx)
+
 	// Update user stats based on the new status
 	switch finalStatus {
 	case "featured":
@@ -196,65 +259,73 @@ func handleStatusChange(s *discordgo.Session, submission *model.Submission, fina
 	case "rejected":
 		db.IncrementRejectedCount(submission.UserID)
 	case "banned":
-		// Apply a 3-day temporary ban and get the updated user stats.
-		updatedUser, err := db.ApplyBan(submission.UserID, 3*24*time.Hour)
+		// Apply a temporary ban of the guild's length, escalating to a permanent ban at the policy's ban count.
+		policy := sanctionPolicy(submission.GuildID)
+		duration := banDuration(policy)
+		updatedUser, permanent, err := applyBan(submission.UserID, policy, duration)
 		if err != nil {
-			log.Printf("Failed to apply temporary ban to user %s: %v", submission.UserID, err)
+			l.Error("failed to apply ban", "target_user_id", submission.UserID, "error", err)
+		} else if permanent {
+			// Notification is now handled by SendBanDMHandler, so we only log here.
+			l.Info("user permanently banned", "target_user_id", submission.UserID, "ban_count", updatedUser.BanCount)
+			if selectedBanReason != "" {
+				sendBanNotification(ctx, submission, true, updatedUser.BanCount, selectedBanReason)
+			}
 		} else {
-			// Check if the user has reached the permanent ban threshold.
-			if updatedUser.BanCount >= 3 {
-				err := db.ApplyPermanentBan(submission.UserID)
-				if err != nil {
-					log.Printf("Failed to apply permanent ban to user %s: %v", submission.UserID, err)
-				} else {
-					// Notification is now handled by SendBanDMHandler, so we only log here.
-					log.Printf("User %s has been permanently banned after reaching %d bans.", submission.UserID, updatedUser.BanCount)
-					if selectedBanReason != "" {
-						sendBanNotification(s, submission.UserID, true, updatedUser.BanCount, selectedBanReason)
-					}
-				}
-			} else {
-				// Notification is now handled by SendBanDMHandler
-				log.Printf("User %s has been temporarily banned for 3 days. This is their %d ban.", submission.UserID, updatedUser.BanCount)
-				if selectedBanReason != "" {
-					sendBanNotification(s, submission.UserID, false, updatedUser.BanCount, selectedBanReason)
-				}
+			// Notification is now handled by SendBanDMHandler
+			l.Info("user temporarily banned", "target_user_id", submission.UserID, "duration", duration.String(), "ban_count", updatedUser.BanCount)
+			if selectedBanReason != "" {
+				sendBanNotification(ctx, submission, false, updatedUser.BanCount, selectedBanReason)
 			}
 		}
 
 		db.IncrementRejectedCount(submission.UserID) // Banned submissions are also considered rejected
 		finalStatus = "rejected"                     // The submission status itself is 'rejected'
+	case "warned":
+		db.IncrementRejectedCount(submission.UserID) // Warned submissions are rejected as well
+		finalStatus = "rejected"
 	}
 
+	tx, err := db.DB.Begin()
+	if err != nil {
+		l.Error("failed to begin transaction", "error", err)
+		return
+	}
+	defer tx.Rollback()
+
 	// Update submission status in the database
-	if err := db.UpdateSubmissionReviewer(submission.ID, finalStatus, reviewerID); err != nil {
-		log.Printf("Failed to update submission status for %s: %v", submission.ID, err)
+	if err := db.UpdateSubmissionReviewerInTx(tx, submission.ID, finalStatus, reviewerID); err != nil {
+		l.Error("failed to update submission status", "error", err)
 		return
 	}
 
-	// If the submission was pending and is now approved or featured, send the publication messages.
+	// If the submission was pending and is now approved or featured, queue the publication
+	// in the same transaction so it is not lost if Discord is unavailable. The role grant and
+	// the author's notification follow once the publication exists.
 	if submission.Status == "pending" && (finalStatus == "approved" || finalStatus == "featured") {
-
-		// 在投稿通过后，分发身份组
-		if shared.GRPCClient != nil && shared.GRPCClient.IsConnected() {
-			go func() {
-				success, err := shared.GRPCClient.AssignRole(submission.GuildID, "0", submission.UserID)
-				if err != nil {
-					log.Printf("为用户 %s 分配身份组失败: %v", submission.UserID, err)
-				} else if success {
-					log.Printf("成功为用户 %s 分配身份组", submission.UserID)
-				} else {
-					log.Printf("为用户 %s 分配身份组未成功，但没有错误返回", submission.UserID)
-				}
-			}()
+		if err := outbox.EnqueueInTx(tx, outbox.ActionPublish, outbox.PublishPayload{
+			SubmissionID:    submission.ID,
+			ReplyToOriginal: replyToOriginal,
+			InteractionID:   logger.InteractionID(ctx),
+		}); err != nil {
+			l.Error("failed to queue publication", "error", err)
+			return
 		}
-		PublishSubmission(s, submission, replyToOriginal)
 	}
+
+	if err := tx.Commit(); err != nil {
+		l.Error("failed to commit status change", "error", err)
+		return
+	}
+	l.Info("submission status changed", "old_status", submission.Status, "new_status", finalStatus)
+	outbox.Kick()
 }
 
 // finalizeReviewMessage updates the original review message to show the final result.
-func finalizeReviewMessage(s *discordgo.Session, i *discordgo.InteractionCreate, submissionID, finalStatus string, rejectionReasons, banReasons []string, cacheID string) {
-	finalEmbed := BuildFinalVoteEmbed(submissionID, finalStatus)
+func finalizeReviewMessage(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, submission *model.Submission, session *vote.Session, finalStatus string, rejectionReasons, banReasons []string, cacheID string) {
+	submissionID := submission.ID
+	blind := isBlindVoting(i.GuildID)
+	finalEmbed := BuildFinalVoteEmbed(session, finalStatus, blind)
 	var components []discordgo.MessageComponent
 
 	// Store reasons in cache and build components based on the final status
@@ -269,6 +340,10 @@ func finalizeReviewMessage(s *discordgo.Session, i *discordgo.InteractionCreate,
 	}
 
 	embeds := i.Message.Embeds
+	if len(embeds) > 0 {
+		// The review is over, a claim no longer applies
+		applyClaimField(embeds[0], model.SubmissionData{})
+	}
 	embeds = append(embeds, finalEmbed)
 
 	_, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
@@ -276,8 +351,10 @@ func finalizeReviewMessage(s *discordgo.Session, i *discordgo.InteractionCreate,
 		Components: &components,
 	})
 	if err != nil {
-		log.Printf("Failed to finalize review message for submission %s: %v", submissionID, err)
+		logger.FromContext(ctx).Error("failed to finalize review message", "error", err)
 	}
+	closeReviewThread(ctx, s, i.Message.ID, submission, session, finalStatus, blind)
+	closeAssignments(ctx, submissionID)
 
 	if finalStatus != "rejected" && finalStatus != "banned" {
 		utils.RemoveFromCache(cacheID)
@@ -290,41 +367,30 @@ func finalizeReviewMessage(s *discordgo.Session, i *discordgo.InteractionCreate,
 	}
 }
 
-// sendBanNotification sends a direct message to a user about their ban status.
-func sendBanNotification(s *discordgo.Session, userID string, isPermanent bool, banCount int, reason string) {
-	channel, err := s.UserChannelCreate(userID)
+// sendRevisionNotification queues a notification asking the author to revise the submission.
+func sendRevisionNotification(ctx context.Context, submission *model.Submission, notes []string, replyToOriginal bool) {
+	err := notify.Send(ctx, notify.KindRevise, submission.UserID, notify.Data{
+		Submission:      submission,
+		Reasons:         notes,
+		ReplyToOriginal: replyToOriginal,
+	})
 	if err != nil {
-		log.Printf("Failed to create DM channel for user %s: %v", userID, err)
-		return
-	}
-
-	embed := &discordgo.MessageEmbed{
-		Title: "来自安利墙的封禁通知",
-		Color: 0xff0000, // Red
-		Fields: []*discordgo.MessageEmbedField{
-			{
-				Name:  "违规用户",
-				Value: fmt.Sprintf("<@%s>", userID),
-			},
-			{
-				Name:  "封禁理由",
-				Value: reason,
-			},
-			{
-				Name:  "这是您的第几次封禁？",
-				Value: fmt.Sprintf("%d", banCount),
-			},
-		},
-	}
-
-	if isPermanent {
-		embed.Description = "您的账户已被安利系统永久拒接投稿权限"
-	} else {
-		embed.Description = "您的账户已被安利系统临时封禁3天累计3次封禁将被永久拒绝投稿"
+		logger.FromContext(ctx).Error("failed to queue revision notification", "target_user_id", submission.UserID, "error", err)
 	}
+}
 
-	_, err = s.ChannelMessageSendEmbed(channel.ID, embed)
+// sendBanNotification queues a notification to a user about their ban status.
+func sendBanNotification(ctx context.Context, submission *model.Submission, isPermanent bool, banCount int, reason string) {
+	policy := sanctionPolicy(submission.GuildID)
+	err := notify.Send(ctx, notify.KindBan, submission.UserID, notify.Data{
+		Submission:         submission,
+		Reasons:            []string{reason},
+		BanCount:           banCount,
+		BanLength:          formatBanLength(banDuration(policy)),
+		PermanentAfterBans: permanentAfterBans(policy),
+		Permanent:          isPermanent,
+	})
 	if err != nil {
-		log.Printf("Failed to send ban notification to user %s: %v", userID, err)
+		logger.FromContext(ctx).Error("failed to queue ban notification", "target_user_id", submission.UserID, "error", err)
 	}
 }
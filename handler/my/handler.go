@@ -7,6 +7,7 @@ import (
 	"amway/utils"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
@@ -32,7 +33,7 @@ func MyAmwayButtonHandler(s *discordgo.Session, i *discordgo.InteractionCreate)
 		return
 	}
 
-	responseData, err := BuildMyAmwayPanelComponents(user, submissions, page, total)
+	responseData, err := BuildMyAmwayPanelComponents(user, submissions, page, total, loadNotifyOptOut(user.ID))
 	if err != nil {
 		log.Printf("Error building my amway panel: %v", err)
 		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
@@ -80,7 +81,7 @@ func MyAmwayPageHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		return
 	}
 
-	responseData, err := BuildMyAmwayPanelComponents(i.Member.User, submissions, page, total)
+	responseData, err := BuildMyAmwayPanelComponents(i.Member.User, submissions, page, total, loadNotifyOptOut(i.Member.User.ID))
 	if err != nil {
 		log.Printf("Error building my amway panel for page %d: %v", page, err)
 		// Handle error
@@ -357,7 +358,7 @@ func BackToMyAmwayHandler(s *discordgo.Session, i *discordgo.InteractionCreate)
 		return
 	}
 
-	responseData, err := BuildMyAmwayPanelComponents(i.Member.User, submissions, page, total)
+	responseData, err := BuildMyAmwayPanelComponents(i.Member.User, submissions, page, total, loadNotifyOptOut(i.Member.User.ID))
 	if err != nil {
 		log.Printf("Error building my amway panel: %v", err)
 		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
@@ -375,3 +376,68 @@ func BackToMyAmwayHandler(s *discordgo.Session, i *discordgo.InteractionCreate)
 		Data: responseData,
 	})
 }
+
+// loadNotifyOptOut returns the user's approval DM preference, defaulting to opted in on errors.
+func loadNotifyOptOut(userID string) bool {
+	optOut, err := db.IsNotifyOptOut(userID)
+	if err != nil {
+		log.Printf("Error getting notification preference for user %s: %v", userID, err)
+		return false
+	}
+	return optOut
+}
+
+// ToggleApprovalDMHandler turns approval and featured DMs on or off and re-renders the panel.
+func ToggleApprovalDMHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	parts := strings.Split(i.MessageComponentData().CustomID, ":")
+	if len(parts) != 3 {
+		return // Invalid custom id
+	}
+	userID := parts[1]
+	page, err := strconv.Atoi(parts[2])
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	// Permission check
+	if i.Member.User.ID != userID {
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "❌ 您不能操作不属于您的面板",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		return
+	}
+
+	optOut := !loadNotifyOptOut(userID)
+	if err := db.SetNotifyOptOut(userID, optOut); err != nil {
+		log.Printf("Error updating notification preference for user %s: %v", userID, err)
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "❌ 更新通知设置时出错",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		return
+	}
+
+	submissions, total, err := db.MyAmwayGetUserSubmissions(userID, page, PageSize)
+	if err != nil {
+		log.Printf("Error getting user submissions for page %d: %v", page, err)
+		return
+	}
+
+	responseData, err := BuildMyAmwayPanelComponents(i.Member.User, submissions, page, total, optOut)
+	if err != nil {
+		log.Printf("Error building my amway panel for page %d: %v", page, err)
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: responseData,
+	})
+}
@@ -14,4 +14,5 @@ func RegisterHandlers() {
 	handler.AddComponentHandlerPrefix("toggle_anonymity_button", ToggleAnonymityHandler)
 	handler.AddComponentHandlerPrefix("delete_amway_button", DeleteAmwayHandler)
 	handler.AddComponentHandlerPrefix("back_to_my_amway", BackToMyAmwayHandler)
+	handler.AddComponentHandlerPrefix("toggle_approval_dm", ToggleApprovalDMHandler)
 }
@@ -16,7 +16,8 @@ const (
 
 // BuildMyAmwayPanelComponents builds the message components for the "My Amway" panel.
 // It displays a user profile card followed by a paginated list of submission cards.
-func BuildMyAmwayPanelComponents(user *discordgo.User, submissions []*model.Submission, page, totalSubmissions int) (*discordgo.InteractionResponseData, error) {
+// notifyOptOut reflects whether the user has turned off approval and featured DMs.
+func BuildMyAmwayPanelComponents(user *discordgo.User, submissions []*model.Submission, page, totalSubmissions int, notifyOptOut bool) (*discordgo.InteractionResponseData, error) {
 	var embeds []*discordgo.MessageEmbed
 
 	// 1. Build User Profile Embed (always the first embed)
@@ -33,6 +34,11 @@ func BuildMyAmwayPanelComponents(user *discordgo.User, submissions []*model.Subm
 				Value:  strconv.Itoa(totalSubmissions),
 				Inline: true,
 			},
+			{
+				Name:   "通过通知",
+				Value:  notifyStatusText(notifyOptOut),
+				Inline: true,
+			},
 		},
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
@@ -101,6 +107,15 @@ func BuildMyAmwayPanelComponents(user *discordgo.User, submissions []*model.Subm
 		CustomID: fmt.Sprintf("modify_amway_button:%s", user.ID),
 	}
 
+	toggleNotifyButton := discordgo.Button{
+		Label:    "🔕 关闭通过通知",
+		Style:    discordgo.SecondaryButton,
+		CustomID: fmt.Sprintf("toggle_approval_dm:%s:%d", user.ID, page),
+	}
+	if notifyOptOut {
+		toggleNotifyButton.Label = "🔔 开启通过通知"
+	}
+
 	// Add a page indicator
 	messageContent := fmt.Sprintf("第 %d / %d 页", page, totalPages)
 	if totalSubmissions == 0 {
@@ -109,7 +124,7 @@ func BuildMyAmwayPanelComponents(user *discordgo.User, submissions []*model.Subm
 
 	components := []discordgo.MessageComponent{
 		discordgo.ActionsRow{
-			Components: []discordgo.MessageComponent{prevButton, nextButton, modifyButton},
+			Components: []discordgo.MessageComponent{prevButton, nextButton, modifyButton, toggleNotifyButton},
 		},
 	}
 
@@ -121,6 +136,13 @@ func BuildMyAmwayPanelComponents(user *discordgo.User, submissions []*model.Subm
 	}, nil
 }
 
+func notifyStatusText(optOut bool) string {
+	if optOut {
+		return "🔕 已关闭"
+	}
+	return "🔔 已开启"
+}
+
 // BuildModifyAmwayModal builds the modal for modifying a submission.
 func BuildModifyAmwayModal(userID string) *discordgo.InteractionResponse {
 	return &discordgo.InteractionResponse{
@@ -227,6 +249,8 @@ func getStatusEmoji(status string) string {
 		return "🔨"
 	case "retracted":
 		return "↩️"
+	case "revision":
+		return "✏️"
 	default:
 		return "⏳" // Pending or unknown
 	}
@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"amway/logger"
+	"amway/metrics"
 	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -36,10 +39,25 @@ func AddModalHandler(customID string, handler func(s *discordgo.Session, i *disc
 // OnInteractionCreate is the main interaction router.
 // It should be registered as the primary interaction handler in main.go.
 func OnInteractionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	logger.FromContext(logger.InteractionContext(i)).Debug("interaction received", "type", i.Type.String())
+
+	name, handler := resolveHandler(i)
+	if handler == nil {
+		return
+	}
+
+	start := time.Now()
+	handler(s, i)
+	metrics.ObserveInteraction(name, i.Type.String(), time.Since(start))
+}
+
+// resolveHandler finds the registered handler for an interaction along with the key it was registered under.
+func resolveHandler(i *discordgo.InteractionCreate) (string, func(s *discordgo.Session, i *discordgo.InteractionCreate)) {
 	switch i.Type {
 	case discordgo.InteractionApplicationCommand:
-		if handler, ok := commandHandlers[i.ApplicationCommandData().Name]; ok {
-			handler(s, i)
+		name := i.ApplicationCommandData().Name
+		if handler, ok := commandHandlers[name]; ok {
+			return name, handler
 		}
 	case discordgo.InteractionMessageComponent:
 		customID := i.MessageComponentData().CustomID
@@ -47,13 +65,11 @@ func OnInteractionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		handlerKey := parts[0]
 
 		if handler, ok := componentHandlers[handlerKey]; ok {
-			handler(s, i)
-			return
+			return handlerKey, handler
 		}
 		for prefix, handler := range componentHandlerPrefixes {
 			if strings.HasPrefix(customID, prefix) {
-				handler(s, i)
-				return
+				return prefix, handler
 			}
 		}
 	case discordgo.InteractionModalSubmit:
@@ -62,7 +78,8 @@ func OnInteractionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		handlerKey := parts[0]
 
 		if handler, ok := modalHandlers[handlerKey]; ok {
-			handler(s, i)
+			return handlerKey, handler
 		}
 	}
+	return "", nil
 }
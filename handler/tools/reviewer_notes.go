@@ -0,0 +1,35 @@
+package tools
+
+import (
+	"amway/model"
+	"fmt"
+	"strings"
+)
+
+// maxNoteLength keeps a single long note from filling a whole embed field.
+const maxNoteLength = 200
+
+// FormatReviewerNotes lists reviewer notes newest first within maxLength runes,
+// summarizing the older notes that do not fit.
+func FormatReviewerNotes(notes []*model.ReviewerNote, maxLength int) string {
+	var value string
+	for idx, n := range notes {
+		content := []rune(strings.ReplaceAll(n.Content, "\n", " "))
+		if len(content) > maxNoteLength {
+			content = append(content[:maxNoteLength-1], '…')
+		}
+		line := fmt.Sprintf("<t:%d:d> <@%s>：%s", n.CreatedAt, n.AuthorID, string(content))
+		if n.SubmissionID != "" {
+			line += fmt.Sprintf("（投稿 `%s`）", n.SubmissionID)
+		}
+		candidate := line
+		if value != "" {
+			candidate = value + "\n" + line
+		}
+		if len([]rune(candidate)) > maxLength-20 {
+			return value + fmt.Sprintf("\n……以及另外 %d 条较早的备注", len(notes)-idx)
+		}
+		value = candidate
+	}
+	return value
+}
@@ -0,0 +1,38 @@
+package tools
+
+import (
+	"amway/vote"
+	"fmt"
+)
+
+// FormatVoteEvent describes a vote history event in one line.
+func FormatVoteEvent(e vote.Event) string {
+	at := fmt.Sprintf("<t:%d:f>", e.Timestamp.Unix())
+	switch e.Event {
+	case vote.EventChange:
+		line := fmt.Sprintf("%s <@%s> 改票 `%s` → `%s`", at, e.VoterID, e.PreviousType, e.Type)
+		if e.Reason != "" && e.Reason != e.PreviousReason {
+			line += fmt.Sprintf("（理由: %s）", e.Reason)
+		}
+		return line
+	case vote.EventRetract:
+		return fmt.Sprintf("%s <@%s> 撤回了 `%s`", at, e.VoterID, e.Type)
+	default:
+		line := fmt.Sprintf("%s <@%s> 投了 `%s`", at, e.VoterID, e.Type)
+		if e.Reason != "" {
+			line += fmt.Sprintf("（理由: %s）", e.Reason)
+		}
+		return line
+	}
+}
+
+// ChangedVoteEvents returns the changes and retractions among the events.
+func ChangedVoteEvents(events []vote.Event) []vote.Event {
+	var changed []vote.Event
+	for _, e := range events {
+		if e.Event == vote.EventChange || e.Event == vote.EventRetract {
+			changed = append(changed, e)
+		}
+	}
+	return changed
+}
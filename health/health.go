@@ -0,0 +1,242 @@
+package health
+
+import (
+	"amway/db"
+	"amway/scheduler"
+	"amway/shared"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	pingTimeout = 2 * time.Second
+	// jobGrace 是任务超过计划时间仍未运行时被视为过期的宽限期
+	jobGrace = 2 * time.Minute
+)
+
+var (
+	configValidated    atomic.Bool
+	commandsRegistered atomic.Bool
+
+	sessionMu sync.RWMutex
+	session   *discordgo.Session
+)
+
+// SetConfigValidated 标记配置已通过校验
+func SetConfigValidated() {
+	configValidated.Store(true)
+}
+
+// SetCommandsRegistered 标记斜杠命令已注册完成
+func SetCommandsRegistered() {
+	commandsRegistered.Store(true)
+}
+
+// SetSession 注入用于检查网关状态的 Discord 会话
+func SetSession(s *discordgo.Session) {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	session = s
+}
+
+// Check 是单个组件的检查结果；Critical 的检查失败会使整体状态失败
+type Check struct {
+	OK       bool        `json:"ok"`
+	Critical bool        `json:"critical"`
+	Status   string      `json:"status"`
+	Error    string      `json:"error,omitempty"`
+	Details  interface{} `json:"details,omitempty"`
+}
+
+// Report 是 /healthz 与 /readyz 返回的 JSON 结构
+type Report struct {
+	Status    string           `json:"status"`
+	Ready     bool             `json:"ready"`
+	Checks    map[string]Check `json:"checks"`
+	Timestamp time.Time        `json:"timestamp"`
+}
+
+// JobFreshness 描述定时任务是否按计划运行
+type JobFreshness struct {
+	Name      string    `json:"name"`
+	Running   bool      `json:"running"`
+	LastRun   time.Time `json:"last_run,omitempty"`
+	NextRun   time.Time `json:"next_run,omitempty"`
+	LastError string    `json:"last_error,omitempty"`
+	Overdue   bool      `json:"overdue"`
+}
+
+// Collect 执行所有检查并生成报告
+func Collect(ctx context.Context) Report {
+	checks := map[string]Check{
+		"discord":   checkDiscord(),
+		"database":  checkDatabase(ctx),
+		"grpc":      checkGRPC(),
+		"scheduler": checkScheduler(),
+		"outbox":    checkOutbox(),
+	}
+
+	healthy := true
+	for _, check := range checks {
+		if check.Critical && !check.OK {
+			healthy = false
+		}
+	}
+
+	report := Report{
+		Status:    "ok",
+		Ready:     healthy && configValidated.Load() && commandsRegistered.Load(),
+		Checks:    checks,
+		Timestamp: time.Now(),
+	}
+	if !healthy {
+		report.Status = "unhealthy"
+	} else {
+		for _, check := range checks {
+			if !check.OK {
+				report.Status = "degraded"
+				break
+			}
+		}
+	}
+	report.Checks["startup"] = Check{
+		OK:     configValidated.Load() && commandsRegistered.Load(),
+		Status: startupStatus(),
+	}
+	return report
+}
+
+func startupStatus() string {
+	switch {
+	case !configValidated.Load():
+		return "config_not_validated"
+	case !commandsRegistered.Load():
+		return "commands_not_registered"
+	default:
+		return "done"
+	}
+}
+
+func checkDiscord() Check {
+	sessionMu.RLock()
+	s := session
+	sessionMu.RUnlock()
+
+	if s == nil {
+		return Check{Critical: true, Status: "no_session"}
+	}
+
+	s.RLock()
+	ready := s.DataReady
+	s.RUnlock()
+	if !ready {
+		return Check{Critical: true, Status: "disconnected"}
+	}
+	return Check{
+		OK:       true,
+		Critical: true,
+		Status:   "connected",
+		Details:  map[string]int64{"heartbeat_latency_ms": s.HeartbeatLatency().Milliseconds()},
+	}
+}
+
+func checkDatabase(ctx context.Context) Check {
+	if db.DB == nil {
+		return Check{Critical: true, Status: "not_initialized"}
+	}
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	if err := db.DB.PingContext(ctx); err != nil {
+		return Check{Critical: true, Status: "unreachable", Error: err.Error()}
+	}
+	return Check{OK: true, Critical: true, Status: "ok"}
+}
+
+func checkGRPC() Check {
+	if shared.GRPCClient == nil {
+		return Check{OK: true, Status: "disabled"}
+	}
+	state := shared.GRPCClient.ConnectionState()
+	return Check{
+		OK:      shared.GRPCClient.IsConnected(),
+		Status:  state.String(),
+		Details: map[string]int64{"reconnects": shared.GRPCClient.ReconnectCount()},
+	}
+}
+
+func checkScheduler() Check {
+	now := time.Now()
+	jobs := make([]JobFreshness, 0)
+	check := Check{OK: true, Status: "ok"}
+
+	for _, status := range scheduler.List() {
+		job := JobFreshness{
+			Name:      status.Name,
+			Running:   status.Running,
+			LastRun:   status.LastRun,
+			NextRun:   status.NextRun,
+			LastError: status.LastError,
+		}
+		// 正在运行的任务不会更新 NextRun，因此只对空闲任务判断是否过期
+		if !status.Running && !status.NextRun.IsZero() && now.After(status.NextRun.Add(jobGrace)) {
+			job.Overdue = true
+			check.OK = false
+			check.Status = "overdue"
+		}
+		jobs = append(jobs, job)
+	}
+	check.Details = jobs
+	return check
+}
+
+func checkOutbox() Check {
+	if db.DB == nil {
+		return Check{Status: "not_initialized"}
+	}
+	counts, err := db.CountOutboxByStatus()
+	if err != nil {
+		return Check{Status: "error", Error: err.Error()}
+	}
+	check := Check{OK: true, Status: "ok", Details: counts}
+	if counts[db.OutboxStatusDead] > 0 {
+		check.OK = false
+		check.Status = "dead_items"
+	}
+	return check
+}
+
+// LivenessHandler 处理 /healthz：关键组件异常时返回 503
+func LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := Collect(r.Context())
+		code := http.StatusOK
+		if report.Status == "unhealthy" {
+			code = http.StatusServiceUnavailable
+		}
+		writeJSON(w, code, report)
+	})
+}
+
+// ReadinessHandler 处理 /readyz：完成启动且关键组件正常时才返回 200
+func ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := Collect(r.Context())
+		code := http.StatusOK
+		if !report.Ready {
+			code = http.StatusServiceUnavailable
+		}
+		writeJSON(w, code, report)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
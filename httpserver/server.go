@@ -0,0 +1,67 @@
+package httpserver
+
+import (
+	"amway/config"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	mux = http.NewServeMux()
+
+	mu     sync.Mutex
+	server *http.Server
+)
+
+// Handle 在内置 HTTP 服务上注册处理器，需在 Start 之前调用
+func Handle(pattern string, handler http.Handler) {
+	mux.Handle(pattern, handler)
+}
+
+// Start 按 http.listen 配置启动 HTTP 服务；未配置监听地址时不启动
+func Start() {
+	addr := config.Cfg.HTTP.Listen
+	if addr == "" {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if server != nil {
+		return
+	}
+
+	server = &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	srv := server
+	go func() {
+		log.Printf("HTTP 服务已启动，监听 %s", addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("HTTP 服务异常退出: %v", err)
+		}
+	}()
+}
+
+// Stop 优雅关闭 HTTP 服务
+func Stop() {
+	mu.Lock()
+	srv := server
+	server = nil
+	mu.Unlock()
+
+	if srv == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("关闭 HTTP 服务失败: %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"amway/config"
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// 上下文字段的统一键名，便于按字段检索同一条投稿的完整链路
+const (
+	KeyInteractionID = "interaction_id"
+	KeyUserID        = "user_id"
+	KeyGuildID       = "guild_id"
+	KeySubmissionID  = "submission_id"
+	KeyCacheID       = "cache_id"
+)
+
+type ctxKey struct{}
+
+type interactionIDKey struct{}
+
+// Setup 按配置初始化全局 slog 日志器：logging.format 选择 json 或 text 输出，
+// debug 为 true 时输出 Debug 级别日志。标准库 log 的输出也会转发到该日志器
+func Setup() {
+	level := slog.LevelInfo
+	if config.Cfg.Debug {
+		level = slog.LevelDebug
+	}
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch strings.ToLower(config.Cfg.Logging.Format) {
+	case "json":
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	default:
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	}
+
+	// slog.SetDefault 同时会让尚未迁移的 log.Printf 以 Info 级别写入该 handler
+	slog.SetDefault(slog.New(handler))
+}
+
+// FromContext 返回携带上下文字段的日志器；ctx 中没有日志器时返回全局日志器
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+			return l
+		}
+	}
+	return slog.Default()
+}
+
+// With 在 ctx 的日志器上追加字段，返回新的 ctx
+func With(ctx context.Context, args ...any) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, ctxKey{}, FromContext(ctx).With(args...))
+}
+
+// WithInteractionID 记录关联的交互 ID，使异步执行的后续步骤（如 outbox 动作）仍能关联到原始交互
+func WithInteractionID(ctx context.Context, interactionID string) context.Context {
+	if interactionID == "" {
+		return ctx
+	}
+	ctx = With(ctx, KeyInteractionID, interactionID)
+	return context.WithValue(ctx, interactionIDKey{}, interactionID)
+}
+
+// InteractionID 返回 ctx 关联的交互 ID，没有时返回空字符串
+func InteractionID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(interactionIDKey{}).(string)
+	return id
+}
+
+// InteractionContext 为一次交互创建带有交互 ID、用户和服务器字段的 ctx
+func InteractionContext(i *discordgo.InteractionCreate) context.Context {
+	ctx := WithInteractionID(context.Background(), i.ID)
+	var args []any
+	if userID := InteractionUserID(i); userID != "" {
+		args = append(args, KeyUserID, userID)
+	}
+	if i.GuildID != "" {
+		args = append(args, KeyGuildID, i.GuildID)
+	}
+	if len(args) == 0 {
+		return ctx
+	}
+	return With(ctx, args...)
+}
+
+// InteractionUserID 返回触发交互的用户 ID，兼容服务器内交互与私信交互
+func InteractionUserID(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
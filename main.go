@@ -8,6 +8,7 @@ import (
 	"amway/bot"
 	"amway/db"
 	"amway/grpc/client"
+	"amway/scheduler"
 	"amway/shared"
 	"log"
 	"os"
@@ -43,6 +44,9 @@ func main() {
 		}
 	}
 
+	// 启动定时任务调度器
+	scheduler.Start()
+
 	// 启动 Discord 机器人
 	bot.Start()
 
@@ -53,6 +57,9 @@ func main() {
 
 	log.Println("正在关闭...")
 
+	// 停止定时任务
+	scheduler.Stop()
+
 	// 关闭 gRPC 连接
 	if shared.GRPCClient != nil && shared.GRPCClient.IsConnected() {
 		shared.GRPCClient.Close()
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// discordTransport 统计 Discord REST 响应中的错误状态码和错误码
+type discordTransport struct {
+	next http.RoundTripper
+}
+
+// WrapTransport 包装 Discord 会话使用的 http.RoundTripper；next 为 nil 时使用默认实现
+func WrapTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &discordTransport{next: next}
+}
+
+func (t *discordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		DiscordErrors.WithLabelValues("network", "").Inc()
+		return resp, err
+	}
+	if resp.StatusCode < http.StatusBadRequest {
+		return resp, nil
+	}
+
+	// 读取错误响应中的 Discord 错误码，再把响应体放回去供 discordgo 解析
+	body, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	code := ""
+	if readErr == nil {
+		var apiErr struct {
+			Code int `json:"code"`
+		}
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Code != 0 {
+			code = strconv.Itoa(apiErr.Code)
+		}
+	}
+	DiscordErrors.WithLabelValues(strconv.Itoa(resp.StatusCode), code).Inc()
+	return resp, nil
+}
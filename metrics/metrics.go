@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "amway"
+
+// Registry 是机器人自身的指标注册表，与 prometheus 的全局注册表隔离
+var Registry = prometheus.NewRegistry()
+
+var (
+	// Interactions 按处理器统计交互次数
+	Interactions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "interactions_total",
+		Help:      "按处理器和交互类型统计的交互次数",
+	}, []string{"handler", "type"})
+
+	// InteractionDuration 统计处理器同步部分的耗时
+	InteractionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "interaction_duration_seconds",
+		Help:      "交互处理器的执行耗时",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"handler"})
+
+	// SubmissionsCreated 统计新建的投稿
+	SubmissionsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "submissions_created_total",
+		Help:      "新建的投稿数量",
+	})
+
+	// VotesCast 按类型统计投票和悔票
+	VotesCast = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "votes_total",
+		Help:      "按类型统计的投票次数",
+	}, []string{"type"})
+
+	// VoteDecisions 按最终结果统计投票决议
+	VoteDecisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "vote_decisions_total",
+		Help:      "按最终状态统计的投票决议",
+	}, []string{"status"})
+
+	// ReviewDuration 统计从投稿到做出决议的时长
+	ReviewDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "review_duration_seconds",
+		Help:      "投稿从提交到审核决议的时长",
+		Buckets:   []float64{60, 300, 900, 1800, 3600, 3 * 3600, 6 * 3600, 12 * 3600, 24 * 3600},
+	})
+
+	// ReactionEvents 统计发布频道中的反应事件
+	ReactionEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reaction_events_total",
+		Help:      "发布频道中按表情和动作统计的反应事件",
+	}, []string{"emoji", "action"})
+
+	// DiscordErrors 统计 Discord REST 请求返回的错误
+	DiscordErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "discord_errors_total",
+		Help:      "按 HTTP 状态码和 Discord 错误码统计的 REST 错误",
+	}, []string{"status", "code"})
+
+	// OutboxActions 按动作类型和结果统计 outbox 执行次数
+	OutboxActions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "outbox_actions_total",
+		Help:      "按动作类型和结果统计的 outbox 执行次数",
+	}, []string{"action", "result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		Interactions,
+		InteractionDuration,
+		SubmissionsCreated,
+		VotesCast,
+		VoteDecisions,
+		ReviewDuration,
+		ReactionEvents,
+		DiscordErrors,
+		OutboxActions,
+		stateCollector{},
+	)
+}
+
+// ObserveInteraction 记录一次交互的处理器和耗时
+func ObserveInteraction(handler, interactionType string, duration time.Duration) {
+	Interactions.WithLabelValues(handler, interactionType).Inc()
+	InteractionDuration.WithLabelValues(handler).Observe(duration.Seconds())
+}
+
+// Handler 返回 /metrics 的 HTTP 处理器
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"amway/db"
+	"amway/grpc/client"
+	"amway/shared"
+	"amway/utils"
+	"log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	submissionsDesc = prometheus.NewDesc(namespace+"_submissions", "按状态统计的投稿数量", []string{"status"}, nil)
+	cacheSizeDesc   = prometheus.NewDesc(namespace+"_cache_entries", "utils 内存缓存的条目数", []string{"cache"}, nil)
+	outboxDesc      = prometheus.NewDesc(namespace+"_outbox_items", "按状态统计的 outbox 动作数量", []string{"status"}, nil)
+	grpcStateDesc   = prometheus.NewDesc(namespace+"_grpc_connection_state", "gRPC 连接状态，当前状态为 1", []string{"state"}, nil)
+	grpcReconnDesc  = prometheus.NewDesc(namespace+"_grpc_reconnects_total", "gRPC 客户端发起重连的次数", nil, nil)
+)
+
+var grpcStates = []client.ConnectionState{client.Disconnected, client.Connecting, client.Connected, client.Reconnecting}
+
+// stateCollector 在每次抓取时读取数据库、缓存和 gRPC 客户端的当前状态
+type stateCollector struct{}
+
+func (stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- submissionsDesc
+	ch <- cacheSizeDesc
+	ch <- outboxDesc
+	ch <- grpcStateDesc
+	ch <- grpcReconnDesc
+}
+
+func (stateCollector) Collect(ch chan<- prometheus.Metric) {
+	if db.DB != nil {
+		if counts, err := db.CountSubmissionsByStatus(); err != nil {
+			log.Printf("统计投稿状态失败: %v", err)
+		} else {
+			for status, count := range counts {
+				ch <- prometheus.MustNewConstMetric(submissionsDesc, prometheus.GaugeValue, float64(count), status)
+			}
+		}
+
+		if counts, err := db.CountOutboxByStatus(); err != nil {
+			log.Printf("统计 outbox 状态失败: %v", err)
+		} else {
+			for status, count := range counts {
+				ch <- prometheus.MustNewConstMetric(outboxDesc, prometheus.GaugeValue, float64(count), status)
+			}
+		}
+	}
+
+	for name, size := range utils.CacheSizes() {
+		ch <- prometheus.MustNewConstMetric(cacheSizeDesc, prometheus.GaugeValue, float64(size), name)
+	}
+
+	if shared.GRPCClient != nil {
+		current := shared.GRPCClient.ConnectionState()
+		for _, state := range grpcStates {
+			value := 0.0
+			if state == current {
+				value = 1
+			}
+			ch <- prometheus.MustNewConstMetric(grpcStateDesc, prometheus.GaugeValue, value, state.String())
+		}
+		ch <- prometheus.MustNewConstMetric(grpcReconnDesc, prometheus.CounterValue, float64(shared.GRPCClient.ReconnectCount()))
+	}
+}
@@ -0,0 +1,17 @@
+package model
+
+// Appeal 记录用户对不通过或封禁结果的一次申诉
+type Appeal struct {
+	ID           int64
+	UserID       string
+	SubmissionID string
+	// Kind 为被申诉的结果类型："reject" 或 "ban"
+	Kind       string
+	Content    string
+	Status     string
+	MessageID  string
+	ResolverID string
+	Note       string
+	CreatedAt  int64
+	ResolvedAt int64
+}
@@ -14,4 +14,8 @@ type SubmissionData struct {
 	ReplyToOriginal  bool
 	SubmissionID     string // Added to track the database submission ID
 	CreatedAt        time.Time
+	Deadline         time.Time // Auto-rejection deadline; zero means CreatedAt plus the default TTL
+	RemindersSent    int       // Number of review reminder checkpoints already announced
+	ClaimedBy        string    // Reviewer currently working on the submission, empty when unclaimed
+	ClaimedUntil     time.Time // When the claim is released automatically
 }
@@ -4,11 +4,19 @@ import "time"
 
 // Config 对应于 config.yaml 的顶级结构
 type Config struct {
-	Token      string     `mapstructure:"token"`
-	Debug      bool       `mapstructure:"debug"`
-	Commands   Commands   `mapstructure:"commands"`
-	AmwayBot   AmwayBot   `mapstructure:"amwayBot"`
-	RoleConfig RoleConfig `mapstructure:"role_config"`
+	Token         string        `mapstructure:"token"`
+	Debug         bool          `mapstructure:"debug"`
+	Commands      Commands      `mapstructure:"commands"`
+	AmwayBot      AmwayBot      `mapstructure:"amwayBot"`
+	RoleConfig    RoleConfig    `mapstructure:"role_config"`
+	Outbox        Outbox        `mapstructure:"outbox"`
+	BulkQueue     BulkQueue     `mapstructure:"bulk_queue"`
+	Logging       Logging       `mapstructure:"logging"`
+	HTTP          HTTP          `mapstructure:"http"`
+	Notifications Notifications `mapstructure:"notifications"`
+	Review        Review        `mapstructure:"review"`
+	Trust         Trust         `mapstructure:"trust"`
+	Sanctions     Sanctions     `mapstructure:"sanctions"`
 }
 
 // PanelState 面板状态
@@ -29,10 +37,128 @@ type Amway struct {
 	PublishChannelID string `mapstructure:"publish_channel_id"`
 }
 
+// Outbox 对应 "outbox" 部分，控制 Discord 副作用的重试策略
+type Outbox struct {
+	MaxAttempts int    `mapstructure:"max_attempts"`
+	BaseDelay   string `mapstructure:"base_delay"`
+	MaxDelay    string `mapstructure:"max_delay"`
+}
+
+// BulkQueue 对应 "bulk_queue" 部分，控制批量 Discord 调用的节奏
+type BulkQueue struct {
+	Interval           string         `mapstructure:"interval"`
+	DefaultConcurrency int            `mapstructure:"default_concurrency"`
+	RouteConcurrency   map[string]int `mapstructure:"route_concurrency"`
+}
+
+// Logging 对应 "logging" 部分
+type Logging struct {
+	// Format 为 "text"（默认）或 "json"
+	Format string `mapstructure:"format"`
+}
+
+// HTTP 对应 "http" 部分，Listen 为空时不启动内置 HTTP 服务
+type HTTP struct {
+	Listen  string `mapstructure:"listen"`
+	Metrics bool   `mapstructure:"metrics"`
+}
+
+// Notifications 对应 "notifications" 部分
+type Notifications struct {
+	// NoticeChannelID 是用户关闭私信时用于提及用户的公告频道
+	NoticeChannelID string `mapstructure:"notice_channel_id"`
+}
+
+// Review 对应 "review" 部分，控制待审投稿的提醒与审核期限
+type Review struct {
+	// ReminderCheckpoints 为投稿等待多久后在审核频道提醒审核员，例如 ["6h", "18h"]，留空则不提醒
+	ReminderCheckpoints []string `mapstructure:"reminder_checkpoints"`
+	// ReviewerRoleIDs 为提醒时提及的身份组，留空时使用 commands.auth.AdminsRoles
+	ReviewerRoleIDs []string `mapstructure:"reviewer_role_ids"`
+	// ExtendBy 为管理员每次延长审核期限的时长，默认 12h
+	ExtendBy string `mapstructure:"extend_by"`
+	// AppealChannelID 为用户申诉发送到的频道，留空则不提供申诉入口
+	AppealChannelID string `mapstructure:"appeal_channel_id"`
+	// ClaimWindow 为审核员认领投稿后的锁定时长，超时自动释放，默认 30m
+	ClaimWindow string `mapstructure:"claim_window"`
+	// AltAccountGroups 为同一人持有的账号分组，组内任一账号的投稿其他账号都不能投票
+	AltAccountGroups [][]string `mapstructure:"alt_account_groups"`
+	// BlindVotingGuilds 为开启盲审的服务器，投票结束前审核消息只显示票数
+	BlindVotingGuilds []string `mapstructure:"blind_voting_guilds"`
+	// AssignmentMode 为指派审核员的方式："round_robin"（轮流）或 "least_loaded"（待审最少者优先），留空则不指派
+	AssignmentMode string `mapstructure:"assignment_mode"`
+	// ReviewerPool 为参与指派的审核员用户 ID
+	ReviewerPool []string `mapstructure:"reviewer_pool"`
+	// AssigneesPerSubmission 为每条投稿指派的审核员人数，默认 2
+	AssigneesPerSubmission int `mapstructure:"assignees_per_submission"`
+	// AssignmentTimeout 为被指派的审核员未投票时转派给他人的时长，默认 12h
+	AssignmentTimeout string `mapstructure:"assignment_timeout"`
+}
+
+// Trust 对应 "trust" 部分，根据用户的投稿记录和账号年龄划分信任等级
+type Trust struct {
+	// Mid 为中等信任的门槛，其投稿在审核队列中置顶
+	Mid TrustThreshold `mapstructure:"mid"`
+	// High 为高信任的门槛，开启 AutoApprove 后其投稿自动通过
+	High TrustThreshold `mapstructure:"high"`
+	// AutoApprove 为 true 时高信任用户的投稿直接发布
+	AutoApprove bool `mapstructure:"auto_approve"`
+	// ReviewSampleRate 为高信任投稿仍随机送审的比例，取值 0 到 1
+	ReviewSampleRate float64 `mapstructure:"review_sample_rate"`
+}
+
+// TrustThreshold 为一个信任等级需要同时满足的条件，MinApproved 为 0 时不启用该等级
+type TrustThreshold struct {
+	// MinApproved 为最少通过（含精选）的投稿数
+	MinApproved int `mapstructure:"min_approved"`
+	// MinFeatured 为最少精选次数
+	MinFeatured int `mapstructure:"min_featured"`
+	// MaxRejectRate 为被拒投稿占已审投稿的最高比例，为 0 时不检查
+	MaxRejectRate float64 `mapstructure:"max_reject_rate"`
+	// MaxBans 为允许的最多被封禁次数
+	MaxBans int `mapstructure:"max_bans"`
+	// MinAccountAge 为 Discord 账号的最短注册时长，例如 "720h"，留空则不检查
+	MinAccountAge string `mapstructure:"min_account_age"`
+}
+
+// Sanctions 对应 "sanctions" 部分，警告与封禁的处罚策略
+type Sanctions struct {
+	// Default 为未单独配置的服务器使用的策略
+	Default SanctionPolicy `mapstructure:"default"`
+	// Guilds 为各服务器单独的策略，键为服务器 ID，配置后整体替换 Default
+	Guilds map[string]SanctionPolicy `mapstructure:"guilds"`
+}
+
+// SanctionPolicy 为一个服务器的处罚策略
+type SanctionPolicy struct {
+	// BanDuration 为投票封禁的时长，默认 72h
+	BanDuration string `mapstructure:"ban_duration"`
+	// PermanentAfterBans 为累计封禁多少次后永久封禁，默认 3
+	PermanentAfterBans int `mapstructure:"permanent_after_bans"`
+	// WarningDecay 为警告的有效期，过期的警告不再计入升级，留空则永不过期
+	WarningDecay string `mapstructure:"warning_decay"`
+	// WarningSteps 为有效警告达到指定次数时的处罚，取不超过当前次数的最高一级
+	WarningSteps []WarningStep `mapstructure:"warning_steps"`
+}
+
+// WarningStep 为警告升级的一级处罚
+type WarningStep struct {
+	// Warnings 为触发该级处罚的有效警告次数
+	Warnings int `mapstructure:"warnings"`
+	// BanDuration 为临时封禁的时长，例如 "72h"
+	BanDuration string `mapstructure:"ban_duration"`
+	// Permanent 为 true 时改为永久封禁
+	Permanent bool `mapstructure:"permanent"`
+}
+
 // Commands 对应 "commands" 部分
 type Commands struct {
 	Allowguils []string `mapstructure:"allowguils"`
 	Auth       Auth     `mapstructure:"auth"`
+	// Global 为 true 时注册全局命令，并清空 allowguils 中各服务器的服务器命令
+	Global bool `mapstructure:"global"`
+	// SkipDevCommands 为 true 时不注册 test_assign_role 等开发命令
+	SkipDevCommands bool `mapstructure:"skip_dev_commands"`
 }
 
 // Auth 对应 "auth" 部分
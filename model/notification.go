@@ -0,0 +1,18 @@
+package model
+
+// Notification 记录一条发给用户的通知及其投递状态
+type Notification struct {
+	ID           int64
+	UserID       string
+	SubmissionID string
+	Kind         string
+	// Payload 是渲染后的消息（JSON），投递时直接发送
+	Payload string
+	// SentCount 是已经通过私信送达的消息条数，重试时从下一条继续
+	SentCount int
+	Status    string
+	Channel   string
+	LastError string
+	CreatedAt int64
+	UpdatedAt int64
+}
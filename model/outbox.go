@@ -0,0 +1,14 @@
+package model
+
+// OutboxItem 表示一条待执行的 Discord 副作用
+type OutboxItem struct {
+	ID            int64
+	Action        string
+	Payload       string
+	Status        string
+	Attempts      int
+	LastError     string
+	NextAttemptAt int64
+	CreatedAt     int64
+	UpdatedAt     int64
+}
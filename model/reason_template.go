@@ -0,0 +1,13 @@
+package model
+
+// ReasonTemplate 是服务器预设的不通过/封禁理由模板
+type ReasonTemplate struct {
+	ID      int64
+	GuildID string
+	// Kind 为模板适用的结果类型："reject" 或 "ban"
+	Kind       string
+	Label      string
+	Text       string
+	UsageCount int
+	CreatedAt  int64
+}
@@ -0,0 +1,12 @@
+package model
+
+// ReviewAssignment 记录一次把投稿指派给审核员的操作
+type ReviewAssignment struct {
+	ID           int64
+	SubmissionID string
+	ReviewerID   string
+	// Status 为 "active"（等待投票）、"done"（已投票或审核结束）或 "expired"（超时后已转派）
+	Status     string
+	AssignedAt int64
+	ClosedAt   int64
+}
@@ -0,0 +1,13 @@
+package model
+
+// ReviewerNote 是审核员之间互相可见、投稿人看不到的备注
+type ReviewerNote struct {
+	ID int64
+	// UserID 为备注针对的用户
+	UserID string
+	// SubmissionID 为添加备注时所在的投稿，通过命令添加时为空
+	SubmissionID string
+	AuthorID     string
+	Content      string
+	CreatedAt    int64
+}
@@ -22,4 +22,6 @@ type Submission struct {
 	Status                string
 	VoteFileID            string
 	ThreadMessageID       string
+	ReviewMessageID       string
+	ReplyToOriginal       bool
 }
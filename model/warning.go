@@ -0,0 +1,20 @@
+package model
+
+// Warning 是用户因投稿违规收到的一次警告
+type Warning struct {
+	ID           int64
+	UserID       string
+	GuildID      string
+	SubmissionID string
+	Reason       string
+	IssuerID     string
+	// Escalation 描述该警告触发的处罚，未触发时为空
+	Escalation string
+	// EscalationKind 为触发的处罚类型，取值见 db.WarningEscalationBan 等常量
+	EscalationKind string
+	// BannedUntil 为该警告触发的临时封禁的结束时间，未触发临时封禁时为 0
+	BannedUntil int64
+	CreatedAt   int64
+	// ExpiresAt 为警告过期的 Unix 时间戳，为 0 时永不过期
+	ExpiresAt int64
+}
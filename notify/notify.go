@@ -0,0 +1,159 @@
+package notify
+
+import (
+	"amway/config"
+	"amway/db"
+	"amway/logger"
+	"amway/model"
+	"amway/outbox"
+	"amway/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// deliveryPayload 是 outbox.ActionNotify 的负载
+type deliveryPayload struct {
+	NotificationID int64 `json:"notification_id"`
+}
+
+func init() {
+	outbox.RegisterHandler(outbox.ActionNotify, deliver)
+
+	// 自动拒绝发生在 utils 的定时任务中，通过回调接入通知服务
+	utils.SetAutoRejectionNotifier(func(submission *model.Submission, reason string) {
+		ctx := logger.With(context.Background(), logger.KeySubmissionID, submission.ID)
+		if err := Send(ctx, KindAutoReject, submission.UserID, Data{
+			Submission: submission,
+			Reasons:    []string{reason},
+		}); err != nil {
+			logger.FromContext(ctx).Error("发送自动拒绝通知失败", "error", err)
+		}
+	})
+}
+
+// Send 渲染通知并记录下来，由 outbox 负责投递与重试
+func Send(ctx context.Context, kind Kind, userID string, d Data) error {
+	messages, err := Render(kind, d)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(messages)
+	if err != nil {
+		return fmt.Errorf("序列化通知失败: %w", err)
+	}
+
+	submissionID := ""
+	if d.Submission != nil {
+		submissionID = d.Submission.ID
+	}
+
+	// 通知记录与投递动作一起写入，避免留下无人投递的通知
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	id, err := db.CreateNotificationInTx(tx, userID, submissionID, string(kind), string(payload))
+	if err != nil {
+		return fmt.Errorf("记录通知失败: %w", err)
+	}
+	if err := outbox.EnqueueInTx(tx, outbox.ActionNotify, deliveryPayload{NotificationID: id}); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交通知失败: %w", err)
+	}
+	outbox.Kick()
+	logger.FromContext(ctx).Debug("通知已排队", "notification_id", id, "kind", kind, "target_user_id", userID)
+	return nil
+}
+
+// deliver 优先私信投递；用户关闭私信时回退到公告频道提及用户
+func deliver(ctx context.Context, s *discordgo.Session, payload []byte) error {
+	var p deliveryPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return outbox.Permanent(err)
+	}
+
+	n, err := db.GetNotification(p.NotificationID)
+	if err != nil {
+		return fmt.Errorf("获取通知 %d 失败: %w", p.NotificationID, err)
+	}
+	if n == nil {
+		return outbox.Permanent(fmt.Errorf("通知 %d 不存在", p.NotificationID))
+	}
+	if n.Status != db.NotificationStatusPending {
+		return nil // 已经投递过
+	}
+
+	var messages []Message
+	if err := json.Unmarshal([]byte(n.Payload), &messages); err != nil {
+		db.UpdateNotificationStatus(n.ID, db.NotificationStatusFailed, "", err.Error())
+		return outbox.Permanent(err)
+	}
+
+	l := logger.FromContext(ctx).With("notification_id", n.ID, "kind", n.Kind, "target_user_id", n.UserID)
+
+	dmErr := sendDM(s, n, messages)
+	if dmErr == nil {
+		return db.UpdateNotificationStatus(n.ID, db.NotificationStatusSent, "dm", "")
+	}
+	if !outbox.IsCannotMessageUser(dmErr) {
+		db.UpdateNotificationStatus(n.ID, db.NotificationStatusPending, "dm", dmErr.Error())
+		return dmErr
+	}
+
+	noticeChannelID := config.Cfg.Notifications.NoticeChannelID
+	if noticeChannelID == "" {
+		l.Warn("用户关闭了私信且未配置公告频道，通知无法送达")
+		return db.UpdateNotificationStatus(n.ID, db.NotificationStatusFailed, "dm", dmErr.Error())
+	}
+
+	if err := sendFallback(s, noticeChannelID, n.UserID); err != nil {
+		db.UpdateNotificationStatus(n.ID, db.NotificationStatusPending, "notice", err.Error())
+		return fmt.Errorf("发送回退通知失败: %w", err)
+	}
+	l.Info("用户关闭了私信，已在公告频道提及用户")
+	return db.UpdateNotificationStatus(n.ID, db.NotificationStatusFallback, "notice", dmErr.Error())
+}
+
+// sendDM 从上次中断处继续私信发送通知的各条消息，每送达一条就记录进度，重试时不会重复发送
+func sendDM(s *discordgo.Session, n *model.Notification, messages []Message) error {
+	if n.SentCount >= len(messages) {
+		return nil
+	}
+	channel, err := s.UserChannelCreate(n.UserID)
+	if err != nil {
+		return fmt.Errorf("创建私信频道失败: %w", err)
+	}
+	for idx := n.SentCount; idx < len(messages); idx++ {
+		msg := messages[idx]
+		if _, err := s.ChannelMessageSendComplex(channel.ID, &discordgo.MessageSend{
+			Content:    msg.Content,
+			Embeds:     msg.Embeds,
+			Components: msg.components(),
+		}); err != nil {
+			return err
+		}
+		if err := db.SetNotificationSentCount(n.ID, idx+1); err != nil {
+			return fmt.Errorf("记录通知投递进度失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// sendFallback 在公告频道提及用户，只提示有新通知，不附带通知内容；
+// 审核理由、封禁次数等内容只保存在通知记录中，避免在公开频道暴露给其他成员
+func sendFallback(s *discordgo.Session, channelID, userID string) error {
+	_, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content: fmt.Sprintf("<@%s> 你有一条来自安利墙的新通知，但我们无法向你发送私信。请开启服务器成员私信以查看通知内容。", userID),
+		AllowedMentions: &discordgo.MessageAllowedMentions{
+			Users: []string{userID},
+		},
+	})
+	return err
+}
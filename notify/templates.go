@@ -0,0 +1,312 @@
+package notify
+
+import (
+	"amway/config"
+	"amway/model"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Kind 是通知的类型，对应一套模板
+type Kind string
+
+const (
+	KindAutoReject Kind = "auto_reject"
+	KindReject     Kind = "reject"
+	KindBan        Kind = "ban"
+	KindApprove    Kind = "approve"
+	KindFeature    Kind = "feature"
+	KindAppeal     Kind = "appeal"
+	KindRevise     Kind = "revise"
+	KindWarn       Kind = "warn"
+)
+
+// Data 是渲染模板所需的数据，不同类型只使用其中一部分字段
+type Data struct {
+	Submission *model.Submission
+	Reasons    []string
+
+	// 封禁：BanLength 为可读的封禁时长，PermanentAfterBans 为 0 时不提示永久封禁的门槛
+	BanCount           int
+	BanLength          string
+	PermanentAfterBans int
+	Permanent          bool
+
+	// 警告：WarningCount 为当前有效警告数，Escalation 描述本次警告触发的处罚
+	WarningCount int
+	Escalation   string
+
+	// 通过 / 精选
+	PublishURL  string
+	ThreadURL   string
+	RoleGranted bool
+	RolePending bool
+
+	// 申诉
+	AppealOverturned bool
+	AppealNote       string
+
+	// 需要修改：重新提交时沿用作者最初对回复原帖的选择
+	ReplyToOriginal bool
+}
+
+// Message 是一条渲染后的私信
+type Message struct {
+	Content    string                    `json:"content,omitempty"`
+	Embeds     []*discordgo.MessageEmbed `json:"embeds,omitempty"`
+	Components []discordgo.ActionsRow    `json:"components,omitempty"`
+}
+
+// appealButtonPrefix 是申诉按钮 CustomID 的前缀，完整格式为 appeal:<kind>:<submissionID>
+const appealButtonPrefix = "appeal:"
+
+// appealComponents 为不通过和封禁通知附上申诉按钮；未配置申诉频道时不提供
+func appealComponents(kind Kind, d Data) []discordgo.ActionsRow {
+	if config.Cfg.Review.AppealChannelID == "" || d.Submission == nil {
+		return nil
+	}
+	return []discordgo.ActionsRow{{
+		Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    "申诉",
+				Style:    discordgo.SecondaryButton,
+				CustomID: fmt.Sprintf("%s%s:%s", appealButtonPrefix, kind, d.Submission.ID),
+				Emoji:    &discordgo.ComponentEmoji{Name: "📮"},
+			},
+		},
+	}}
+}
+
+// components 将按钮行转换为发送消息所需的类型
+func (m Message) components() []discordgo.MessageComponent {
+	var components []discordgo.MessageComponent
+	for _, row := range m.Components {
+		components = append(components, row)
+	}
+	return components
+}
+
+type template func(d Data) []Message
+
+var templates = map[Kind]template{
+	KindAutoReject: renderAutoReject,
+	KindReject:     renderReject,
+	KindBan:        renderBan,
+	KindApprove:    renderApprove,
+	KindFeature:    renderFeature,
+	KindAppeal:     renderAppeal,
+	KindRevise:     renderRevise,
+	KindWarn:       renderWarn,
+}
+
+// Render 按类型渲染通知消息
+func Render(kind Kind, d Data) ([]Message, error) {
+	tmpl, ok := templates[kind]
+	if !ok {
+		return nil, fmt.Errorf("未知的通知类型: %s", kind)
+	}
+	return tmpl(d), nil
+}
+
+func submissionTitle(d Data) string {
+	if d.Submission == nil {
+		return "-"
+	}
+	if d.Submission.RecommendTitle != "" {
+		return d.Submission.RecommendTitle
+	}
+	if d.Submission.OriginalTitle != "" {
+		return d.Submission.OriginalTitle
+	}
+	return d.Submission.ID
+}
+
+func userIDOf(d Data) string {
+	if d.Submission == nil {
+		return "-"
+	}
+	return d.Submission.UserID
+}
+
+// maxFieldLength 为 Discord embed 字段的长度上限，超出时私信会被拒绝
+const maxFieldLength = 1024
+
+func reasonList(reasons []string) string {
+	if len(reasons) == 0 {
+		return "未提供理由"
+	}
+	list := []rune("- " + strings.Join(reasons, "\n- "))
+	if len(list) > maxFieldLength {
+		list = append(list[:maxFieldLength-1], '…')
+	}
+	return string(list)
+}
+
+// contentCopy 附上投稿原文，方便用户复制修改后重新投稿
+func contentCopy(d Data) []Message {
+	if d.Submission == nil || d.Submission.RecommendContent == "" {
+		return nil
+	}
+	return []Message{{Content: fmt.Sprintf("```\n%s\n```", d.Submission.RecommendContent)}}
+}
+
+func renderAutoReject(d Data) []Message {
+	embed := &discordgo.MessageEmbed{
+		Title:       "您的投稿未通过审核",
+		Description: "您提交的以下安利投稿在审核期限内未完成审核，已被系统自动关闭：",
+		Color:       0xFF0000,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "您的安利标题", Value: submissionTitle(d)},
+			{Name: "原因", Value: reasonList(d.Reasons)},
+		},
+		Footer: &discordgo.MessageEmbedFooter{Text: "感谢您的参与，期待您下次的分享！"},
+	}
+	return append([]Message{{Embeds: []*discordgo.MessageEmbed{embed}}}, contentCopy(d)...)
+}
+
+func renderReject(d Data) []Message {
+	embed := &discordgo.MessageEmbed{
+		Title:       "您的投稿未通过审核",
+		Description: "很遗憾，您提交的以下安利投稿未通过审核：",
+		Color:       0xFF0000,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "您的安利标题", Value: submissionTitle(d)},
+			{Name: "不通过理由", Value: reasonList(d.Reasons)},
+		},
+		Footer: &discordgo.MessageEmbedFooter{Text: "感谢您的参与，期待您下次的分享！"},
+	}
+	// 先发送 embed，再发送纯文本原文以便复制
+	return append([]Message{{Embeds: []*discordgo.MessageEmbed{embed}, Components: appealComponents(KindReject, d)}}, contentCopy(d)...)
+}
+
+func renderBan(d Data) []Message {
+	embed := &discordgo.MessageEmbed{
+		Title: "来自安利墙的封禁通知",
+		Color: 0xff0000,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "违规用户", Value: fmt.Sprintf("<@%s>", userIDOf(d))},
+			{Name: "封禁理由", Value: reasonList(d.Reasons)},
+			{Name: "这是您的第几次封禁？", Value: fmt.Sprintf("%d", d.BanCount)},
+		},
+	}
+	if d.Permanent {
+		embed.Description = "您的账户已被安利系统永久拒接投稿权限"
+	} else {
+		embed.Description = fmt.Sprintf("您的账户已被安利系统临时封禁%s", d.BanLength)
+		if d.PermanentAfterBans > 0 {
+			embed.Description += fmt.Sprintf("，累计%d次封禁将被永久拒绝投稿", d.PermanentAfterBans)
+		}
+	}
+	return []Message{{Embeds: []*discordgo.MessageEmbed{embed}, Components: appealComponents(KindBan, d)}}
+}
+
+func renderPublished(d Data, title string, color int) []Message {
+	embed := &discordgo.MessageEmbed{
+		Title: title,
+		Color: color,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "您的安利标题", Value: submissionTitle(d)},
+		},
+		Footer: &discordgo.MessageEmbedFooter{Text: "感谢您的分享！"},
+	}
+	if d.PublishURL != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "安利消息",
+			Value: fmt.Sprintf("[点击查看](%s)", d.PublishURL),
+		})
+	}
+	if d.ThreadURL != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "原帖回复",
+			Value: fmt.Sprintf("[点击查看](%s)", d.ThreadURL),
+		})
+	}
+	switch {
+	case d.RoleGranted:
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "身份组",
+			Value: "已为您发放安利身份组",
+		})
+	case d.RolePending:
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "身份组",
+			Value: "身份组发放暂时失败，稍后会自动重试",
+		})
+	}
+	embed.Description = "不想再收到此类通知？可以在「我的」面板中关闭。"
+	return []Message{{Embeds: []*discordgo.MessageEmbed{embed}}}
+}
+
+func renderApprove(d Data) []Message {
+	return renderPublished(d, "您的安利已通过审核 🎉", 0x2ea043)
+}
+
+func renderFeature(d Data) []Message {
+	return renderPublished(d, "您的安利被评为精选 🌟", 0xFFD700)
+}
+
+func renderAppeal(d Data) []Message {
+	embed := &discordgo.MessageEmbed{
+		Title: "申诉结果",
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "您的安利标题", Value: submissionTitle(d)},
+		},
+	}
+	if d.AppealOverturned {
+		embed.Description = "您的申诉已通过，原审核结果已撤销。"
+		embed.Color = 0x2ea043
+	} else {
+		embed.Description = "您的申诉已被驳回，维持原审核结果。"
+		embed.Color = 0xFF0000
+	}
+	if d.AppealNote != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "管理员备注", Value: d.AppealNote})
+	}
+	return []Message{{Embeds: []*discordgo.MessageEmbed{embed}}}
+}
+
+func renderRevise(d Data) []Message {
+	embed := &discordgo.MessageEmbed{
+		Title:       "您的投稿需要修改",
+		Description: "审核员认为您的安利很不错，但还有一些地方需要修改。点击下方按钮即可修改内容并重新提交审核，投稿编号与之前的审核记录都会保留。",
+		Color:       0xFFA500,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "您的安利标题", Value: submissionTitle(d)},
+			{Name: "修改意见", Value: reasonList(d.Reasons)},
+		},
+	}
+	var components []discordgo.ActionsRow
+	if d.Submission != nil {
+		components = []discordgo.ActionsRow{{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "修改投稿",
+					Style:    discordgo.PrimaryButton,
+					CustomID: fmt.Sprintf("revise_submission:%s:%t", d.Submission.ID, d.ReplyToOriginal),
+					Emoji:    &discordgo.ComponentEmoji{Name: "✏️"},
+				},
+			},
+		}}
+	}
+	return []Message{{Embeds: []*discordgo.MessageEmbed{embed}, Components: components}}
+}
+
+func renderWarn(d Data) []Message {
+	embed := &discordgo.MessageEmbed{
+		Title:       "来自安利墙的警告",
+		Description: "您提交的以下安利投稿未通过审核，并因违反投稿规范收到一次警告：",
+		Color:       0xFFA500,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "您的安利标题", Value: submissionTitle(d)},
+			{Name: "警告理由", Value: reasonList(d.Reasons)},
+			{Name: "当前有效警告", Value: fmt.Sprintf("%d 次", d.WarningCount)},
+		},
+		Footer: &discordgo.MessageEmbedFooter{Text: "警告会在一段时间后过期，累计过多警告将被封禁"},
+	}
+	if d.Escalation != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "处罚", Value: d.Escalation})
+	}
+	return append([]Message{{Embeds: []*discordgo.MessageEmbed{embed}}}, contentCopy(d)...)
+}
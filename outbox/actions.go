@@ -0,0 +1,164 @@
+package outbox
+
+import (
+	"amway/shared"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Discord REST 错误码
+const (
+	discordUnknownChannel     = 10003
+	discordUnknownMessage     = 10008
+	discordUnknownMember      = 10007
+	discordUnknownRole        = 10011
+	discordCannotMessageUser  = 50007
+	discordThreadMemberLimit  = 30033
+	discordMissingPermissions = 50013
+)
+
+// PublishPayload 是 ActionPublish 的负载
+type PublishPayload struct {
+	SubmissionID    string `json:"submission_id"`
+	ReplyToOriginal bool   `json:"reply_to_original"`
+	// InteractionID 关联触发发布的投票交互，仅用于日志
+	InteractionID string `json:"interaction_id,omitempty"`
+}
+
+// NotifyThreadPayload 是 ActionNotifyThread 的负载
+type NotifyThreadPayload struct {
+	SubmissionID     string `json:"submission_id"`
+	PublishChannelID string `json:"publish_channel_id"`
+	PublishMessageID string `json:"publish_message_id"`
+	InteractionID    string `json:"interaction_id,omitempty"`
+}
+
+// AnnounceApprovalPayload 是 ActionAnnounceApproval 的负载
+type AnnounceApprovalPayload struct {
+	SubmissionID  string `json:"submission_id"`
+	InteractionID string `json:"interaction_id,omitempty"`
+}
+
+// DMUserPayload 是 ActionDMUser 的负载
+type DMUserPayload struct {
+	UserID  string                    `json:"user_id"`
+	Content string                    `json:"content,omitempty"`
+	Embeds  []*discordgo.MessageEmbed `json:"embeds,omitempty"`
+}
+
+// AssignRolePayload 是 ActionAssignRole 的负载
+type AssignRolePayload struct {
+	GuildID  string `json:"guild_id"`
+	ConfigID string `json:"config_id"`
+	UserID   string `json:"user_id"`
+}
+
+// RevokeRolePayload 是 ActionRevokeRole 的负载
+type RevokeRolePayload struct {
+	GuildID string `json:"guild_id"`
+	UserID  string `json:"user_id"`
+	RoleID  string `json:"role_id"`
+}
+
+// DeleteMessagePayload 是 ActionDeleteMessage 的负载
+type DeleteMessagePayload struct {
+	ChannelID string `json:"channel_id"`
+	MessageID string `json:"message_id"`
+}
+
+func handleDMUser(ctx context.Context, s *discordgo.Session, payload []byte) error {
+	var p DMUserPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return Permanent(err)
+	}
+
+	channel, err := s.UserChannelCreate(p.UserID)
+	if err != nil {
+		return fmt.Errorf("创建私信频道失败: %w", err)
+	}
+
+	_, err = s.ChannelMessageSendComplex(channel.ID, &discordgo.MessageSend{
+		Content: p.Content,
+		Embeds:  p.Embeds,
+	})
+	if err != nil {
+		if IsDiscordError(err, discordCannotMessageUser) {
+			// 用户关闭了私信，重试没有意义
+			return Permanent(err)
+		}
+		return fmt.Errorf("发送私信失败: %w", err)
+	}
+	return nil
+}
+
+func handleAssignRole(ctx context.Context, s *discordgo.Session, payload []byte) error {
+	var p AssignRolePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return Permanent(err)
+	}
+
+	if shared.GRPCClient == nil {
+		return Permanent(fmt.Errorf("gRPC 客户端未启用"))
+	}
+
+	success, err := shared.GRPCClient.AssignRole(p.GuildID, p.ConfigID, p.UserID)
+	if err != nil {
+		return err
+	}
+	if !success {
+		return fmt.Errorf("为用户 %s 分配身份组未成功", p.UserID)
+	}
+	return nil
+}
+
+// handleRevokeRole 直接通过 Discord 移除身份组，身份组中心没有对应的收回接口
+func handleRevokeRole(ctx context.Context, s *discordgo.Session, payload []byte) error {
+	var p RevokeRolePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return Permanent(err)
+	}
+
+	err := s.GuildMemberRoleRemove(p.GuildID, p.UserID, p.RoleID)
+	if err != nil {
+		if IsDiscordError(err, discordUnknownMember, discordUnknownRole) {
+			return nil // 用户已离开服务器或身份组已删除，视为成功
+		}
+		if IsDiscordError(err, discordMissingPermissions) {
+			return Permanent(err)
+		}
+		return fmt.Errorf("收回身份组失败: %w", err)
+	}
+	return nil
+}
+
+func handleDeleteMessage(ctx context.Context, s *discordgo.Session, payload []byte) error {
+	var p DeleteMessagePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return Permanent(err)
+	}
+
+	err := s.ChannelMessageDelete(p.ChannelID, p.MessageID)
+	if err != nil {
+		if IsDiscordError(err, discordUnknownMessage, discordUnknownChannel) {
+			return nil // 消息已经不存在，视为成功
+		}
+		if IsDiscordError(err, discordMissingPermissions) {
+			return Permanent(err)
+		}
+		return fmt.Errorf("删除消息失败: %w", err)
+	}
+	return nil
+}
+
+// IsCannotMessageUser 判断错误是否为用户关闭了私信
+func IsCannotMessageUser(err error) bool {
+	return IsDiscordError(err, discordCannotMessageUser)
+}
+
+// IsThreadMemberLimit 判断错误是否为帖子成员数达到上限
+func IsThreadMemberLimit(err error) bool {
+	return IsDiscordError(err, discordThreadMemberLimit)
+}
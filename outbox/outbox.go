@@ -0,0 +1,258 @@
+package outbox
+
+import (
+	"amway/config"
+	"amway/db"
+	"amway/logger"
+	"amway/metrics"
+	"amway/model"
+	"amway/scheduler"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// ActionPublish 将通过审核的投稿发布到发布频道
+	ActionPublish = "publish"
+	// ActionNotifyThread 在原帖下回复安利通知
+	ActionNotifyThread = "notify_thread"
+	// ActionDMUser 向用户发送私信
+	ActionDMUser = "dm_user"
+	// ActionAssignRole 通过 gRPC 为用户分配身份组
+	ActionAssignRole = "assign_role"
+	// ActionRevokeRole 收回通过审核时分配的身份组
+	ActionRevokeRole = "revoke_role"
+	// ActionDeleteMessage 删除一条频道消息
+	ActionDeleteMessage = "delete_message"
+	// ActionNotify 投递一条通知服务记录的通知
+	ActionNotify = "notify"
+	// ActionAnnounceApproval 在发布后为作者分配身份组并发送通过/精选通知
+	ActionAnnounceApproval = "announce_approval"
+)
+
+const (
+	workerJobName      = "outbox_worker"
+	workerBatchSize    = 20
+	defaultMaxAttempts = 8
+	defaultBaseDelay   = 30 * time.Second
+	defaultMaxDelay    = time.Hour
+	doneRetention      = 7 * 24 * time.Hour
+)
+
+// ErrPermanent 表示动作不可能通过重试成功，应直接进入死信
+var ErrPermanent = errors.New("不可恢复的错误")
+
+// Permanent 将错误标记为不可恢复
+func Permanent(err error) error {
+	return fmt.Errorf("%w: %v", ErrPermanent, err)
+}
+
+// Handler 执行一种动作；返回 nil 表示成功（包括已经执行过的幂等情况）
+type Handler func(ctx context.Context, s *discordgo.Session, payload []byte) error
+
+var (
+	handlersMu sync.RWMutex
+	handlers   = make(map[string]Handler)
+
+	sessionMu sync.RWMutex
+	session   *discordgo.Session
+)
+
+func init() {
+	RegisterHandler(ActionDMUser, handleDMUser)
+	RegisterHandler(ActionAssignRole, handleAssignRole)
+	RegisterHandler(ActionRevokeRole, handleRevokeRole)
+	RegisterHandler(ActionDeleteMessage, handleDeleteMessage)
+
+	scheduler.Register(scheduler.Job{
+		Name:     workerJobName,
+		Schedule: scheduler.Every(15 * time.Second),
+		Run:      processDue,
+	})
+	// 已完成的动作只保留一段时间用于排查
+	scheduler.Register(scheduler.Job{
+		Name:     "outbox_cleanup",
+		Schedule: scheduler.MustParseCron("30 4 * * *"),
+		Run: func(ctx context.Context) error {
+			removed, err := db.PurgeDoneOutboxItems(time.Now().Add(-doneRetention))
+			if err != nil {
+				return err
+			}
+			if removed > 0 {
+				log.Printf("已清理 %d 条已完成的 outbox 动作", removed)
+			}
+			return nil
+		},
+	})
+}
+
+// RegisterHandler 为动作注册执行函数
+func RegisterHandler(action string, handler Handler) {
+	handlersMu.Lock()
+	defer handlersMu.Unlock()
+	handlers[action] = handler
+}
+
+// SetSession 注入执行动作所使用的 Discord 会话
+func SetSession(s *discordgo.Session) {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	session = s
+}
+
+// Session 返回注入的 Discord 会话，尚未就绪时为 nil；供同样需要会话的定时任务使用
+func Session() *discordgo.Session {
+	return getSession()
+}
+
+func getSession() *discordgo.Session {
+	sessionMu.RLock()
+	defer sessionMu.RUnlock()
+	return session
+}
+
+// Enqueue 添加一条动作并立即唤醒 worker
+func Enqueue(action string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("序列化 outbox 负载失败: %w", err)
+	}
+	if _, err := db.EnqueueOutbox(action, string(data)); err != nil {
+		return fmt.Errorf("写入 outbox 失败: %w", err)
+	}
+	Kick()
+	return nil
+}
+
+// EnqueueInTx 在事务中添加一条动作；调用方应在提交后调用 Kick
+func EnqueueInTx(tx *sql.Tx, action string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("序列化 outbox 负载失败: %w", err)
+	}
+	if _, err := db.EnqueueOutboxInTx(tx, action, string(data)); err != nil {
+		return fmt.Errorf("写入 outbox 失败: %w", err)
+	}
+	return nil
+}
+
+// Kick 让 worker 尽快处理待执行的动作
+func Kick() {
+	if err := scheduler.Trigger(workerJobName); err != nil && !errors.Is(err, scheduler.ErrJobRunning) {
+		log.Printf("唤醒 outbox worker 失败: %v", err)
+	}
+}
+
+// processDue 执行所有已到期的动作
+func processDue(ctx context.Context) error {
+	s := getSession()
+	if s == nil {
+		return nil // 会话尚未就绪
+	}
+
+	items, err := db.GetDueOutboxItems(workerBatchSize)
+	if err != nil {
+		return fmt.Errorf("获取待执行的 outbox 动作失败: %w", err)
+	}
+
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		execute(ctx, s, item)
+	}
+	return nil
+}
+
+func execute(ctx context.Context, s *discordgo.Session, item *model.OutboxItem) {
+	handlersMu.RLock()
+	handler, ok := handlers[item.Action]
+	handlersMu.RUnlock()
+
+	if !ok {
+		log.Printf("outbox 动作 %d 的类型 %s 没有注册处理函数，移入死信", item.ID, item.Action)
+		metrics.OutboxActions.WithLabelValues(item.Action, "dead").Inc()
+		if err := db.MarkOutboxDead(item.ID, "未知的动作类型"); err != nil {
+			log.Printf("更新 outbox 动作 %d 状态失败: %v", item.ID, err)
+		}
+		return
+	}
+
+	ctx = logger.With(ctx, "outbox_id", item.ID, "action", item.Action)
+	err := handler(ctx, s, []byte(item.Payload))
+	if err == nil {
+		metrics.OutboxActions.WithLabelValues(item.Action, "done").Inc()
+		if err := db.MarkOutboxDone(item.ID); err != nil {
+			log.Printf("更新 outbox 动作 %d 状态失败: %v", item.ID, err)
+		}
+		return
+	}
+
+	attempts := item.Attempts + 1
+	maxAttempts := config.Cfg.Outbox.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	if errors.Is(err, ErrPermanent) || attempts >= maxAttempts {
+		log.Printf("outbox 动作 %d (%s) 在第 %d 次尝试后进入死信: %v", item.ID, item.Action, attempts, err)
+		metrics.OutboxActions.WithLabelValues(item.Action, "dead").Inc()
+		if err := db.MarkOutboxDead(item.ID, err.Error()); err != nil {
+			log.Printf("更新 outbox 动作 %d 状态失败: %v", item.ID, err)
+		}
+		return
+	}
+
+	metrics.OutboxActions.WithLabelValues(item.Action, "retry").Inc()
+	delay := backoffDelay(item.Attempts)
+	log.Printf("outbox 动作 %d (%s) 第 %d 次尝试失败，%v 后重试: %v", item.ID, item.Action, attempts, delay, err)
+	if err := db.MarkOutboxRetry(item.ID, err.Error(), time.Now().Add(delay)); err != nil {
+		log.Printf("更新 outbox 动作 %d 状态失败: %v", item.ID, err)
+	}
+}
+
+// backoffDelay 计算第 attempt 次失败后的指数退避时长
+func backoffDelay(attempt int) time.Duration {
+	baseDelay := parseDurationOr(config.Cfg.Outbox.BaseDelay, defaultBaseDelay)
+	maxDelay := parseDurationOr(config.Cfg.Outbox.MaxDelay, defaultMaxDelay)
+
+	delay := time.Duration(float64(baseDelay) * math.Pow(2, float64(attempt)))
+	if delay > maxDelay || delay <= 0 {
+		delay = maxDelay
+	}
+	return delay
+}
+
+func parseDurationOr(value string, fallback time.Duration) time.Duration {
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+// IsDiscordError 判断错误是否为指定错误码的 Discord REST 错误
+func IsDiscordError(err error, codes ...int) bool {
+	var restErr *discordgo.RESTError
+	if !errors.As(err, &restErr) || restErr.Message == nil {
+		return false
+	}
+	for _, code := range codes {
+		if restErr.Message.Code == code {
+			return true
+		}
+	}
+	return false
+}
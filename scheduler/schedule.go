@@ -0,0 +1,184 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule 决定任务的下一次运行时间
+type Schedule interface {
+	// Next 返回严格晚于 from 的下一次运行时间
+	Next(from time.Time) time.Time
+	String() string
+}
+
+// intervalSchedule 以固定间隔运行
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+// Every 返回一个按固定间隔运行的调度
+func Every(interval time.Duration) Schedule {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return intervalSchedule{interval: interval}
+}
+
+func (s intervalSchedule) Next(from time.Time) time.Time {
+	return from.Add(s.interval)
+}
+
+func (s intervalSchedule) String() string {
+	return "every " + s.interval.String()
+}
+
+// cronSchedule 是一个简化的五段式 cron 表达式（分 时 日 月 周）
+type cronSchedule struct {
+	expr   string
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	// 日与周都被限制（不以 * 开头）时按标准 cron 的规则，满足其中之一即可
+	dayOr bool
+}
+
+type cronField struct {
+	min, max int
+}
+
+var cronFields = []cronField{
+	{0, 59}, // 分
+	{0, 23}, // 时
+	{1, 31}, // 日
+	{1, 12}, // 月
+	{0, 6},  // 周（0 为周日）
+}
+
+// ParseCron 解析五段式 cron 表达式，支持 *、*/n、a/n、a-b、a-b/n 以及逗号分隔的列表
+func ParseCron(expr string) (Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("cron 表达式需要 %d 段，实际为 %d 段: %q", len(cronFields), len(fields), expr)
+	}
+
+	var masks [5]uint64
+	for idx, field := range fields {
+		mask, err := parseCronField(field, cronFields[idx])
+		if err != nil {
+			return nil, fmt.Errorf("解析 cron 表达式 %q 失败: %w", expr, err)
+		}
+		masks[idx] = mask
+	}
+
+	return cronSchedule{
+		expr:   expr,
+		minute: masks[0],
+		hour:   masks[1],
+		dom:    masks[2],
+		month:  masks[3],
+		dow:    masks[4],
+		dayOr:  !strings.HasPrefix(fields[2], "*") && !strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+// MustParseCron 与 ParseCron 相同，但在表达式无效时 panic
+func MustParseCron(expr string) Schedule {
+	schedule, err := ParseCron(expr)
+	if err != nil {
+		panic(err)
+	}
+	return schedule
+}
+
+func parseCronField(field string, bounds cronField) (uint64, error) {
+	var mask uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		hasStep := false
+		if idx := strings.Index(part, "/"); idx != -1 {
+			n, err := strconv.Atoi(part[idx+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("无效的步长 %q", part)
+			}
+			step = n
+			hasStep = true
+			part = part[:idx]
+		}
+
+		low, high := bounds.min, bounds.max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			rangeParts := strings.SplitN(part, "-", 2)
+			a, errA := strconv.Atoi(rangeParts[0])
+			b, errB := strconv.Atoi(rangeParts[1])
+			if errA != nil || errB != nil {
+				return 0, fmt.Errorf("无效的范围 %q", part)
+			}
+			low, high = a, b
+		default:
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return 0, fmt.Errorf("无效的值 %q", part)
+			}
+			low, high = n, n
+			if hasStep {
+				// 与标准 cron 一致，a/n 表示从 a 开始到最大值每隔 n
+				high = bounds.max
+			}
+		}
+
+		if low < bounds.min || high > bounds.max || low > high {
+			return 0, fmt.Errorf("值 %q 超出范围 %d-%d", part, bounds.min, bounds.max)
+		}
+		for v := low; v <= high; v += step {
+			mask |= 1 << uint(v)
+		}
+	}
+	return mask, nil
+}
+
+func (s cronSchedule) Next(from time.Time) time.Time {
+	t := from.Truncate(time.Minute).Add(time.Minute)
+	// 最多向后搜索五年，防止无法满足的表达式（如 2 月 31 日）导致死循环
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches 判断 t 所在的日期是否满足日与周两段
+func (s cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.dayOr {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+func (s cronSchedule) String() string {
+	return "cron " + s.expr
+}
@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+// 2026-10-19 是周一
+var cronFrom = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+func nextRuns(t *testing.T, expr string, n int) []time.Time {
+	t.Helper()
+	schedule, err := ParseCron(expr)
+	if err != nil {
+		t.Fatalf("ParseCron(%q) error = %v", expr, err)
+	}
+	var runs []time.Time
+	from := cronFrom
+	for len(runs) < n {
+		from = schedule.Next(from)
+		if from.IsZero() {
+			break
+		}
+		runs = append(runs, from)
+	}
+	return runs
+}
+
+func assertRuns(t *testing.T, expr string, want ...time.Time) {
+	t.Helper()
+	got := nextRuns(t, expr, len(want))
+	if len(got) != len(want) {
+		t.Fatalf("%q: got %d runs %v, want %v", expr, len(got), got, want)
+	}
+	for idx := range want {
+		if !got[idx].Equal(want[idx]) {
+			t.Errorf("%q: run %d = %v, want %v", expr, idx, got[idx], want[idx])
+		}
+	}
+}
+
+func at(day, hour, minute int) time.Time {
+	return time.Date(2026, 10, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestCronSteps(t *testing.T) {
+	assertRuns(t, "*/20 * * * *", at(19, 12, 20), at(19, 12, 40), at(19, 13, 0))
+	// A single value with a step runs from that value to the end of the range
+	assertRuns(t, "5/20 * * * *", at(19, 12, 5), at(19, 12, 25), at(19, 12, 45), at(19, 13, 5))
+	assertRuns(t, "10-30/10 * * * *", at(19, 12, 10), at(19, 12, 20), at(19, 12, 30), at(19, 13, 10))
+	assertRuns(t, "0 9,18 * * *", at(19, 18, 0), at(20, 9, 0))
+}
+
+func TestCronDayFields(t *testing.T) {
+	// Both day fields restricted: either one matches, like standard cron
+	assertRuns(t, "0 9 25 * 3", at(21, 9, 0), at(25, 9, 0), at(28, 9, 0))
+	// Only the weekday restricted
+	assertRuns(t, "0 9 * * 5", at(23, 9, 0), at(30, 9, 0))
+	// A field starting with * counts as unrestricted, so both must match
+	assertRuns(t, "0 9 */2 * 1", time.Date(2026, 11, 9, 9, 0, 0, 0, time.UTC))
+}
+
+func TestCronUnsatisfiable(t *testing.T) {
+	schedule := MustParseCron("0 0 31 2 *")
+	if next := schedule.Next(cronFrom); !next.IsZero() {
+		t.Errorf("Next() = %v, want zero time", next)
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 7",
+		"*/0 * * * *",
+		"70/5 * * * *",
+		"30-10 * * * *",
+		"a * * * *",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) error = nil, want an error", expr)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrJobNotFound 表示没有以该名称注册的任务
+	ErrJobNotFound = errors.New("任务不存在")
+	// ErrJobRunning 表示任务正在运行，本次触发被跳过
+	ErrJobRunning = errors.New("任务正在运行")
+)
+
+// JobFunc 是任务的执行体，应在 ctx 被取消时尽快返回
+type JobFunc func(ctx context.Context) error
+
+// Job 描述一个具名的定时任务
+type Job struct {
+	Name     string
+	Schedule Schedule
+	Run      JobFunc
+}
+
+// Status 是任务运行状态的快照
+type Status struct {
+	Name         string
+	Schedule     string
+	Running      bool
+	Runs         int
+	LastRun      time.Time
+	LastDuration time.Duration
+	LastError    string
+	NextRun      time.Time
+}
+
+type entry struct {
+	job     Job
+	trigger chan struct{}
+
+	mu      sync.Mutex
+	running bool
+	status  Status
+}
+
+// Scheduler 管理一组具名任务，保证同一任务不会并发执行
+type Scheduler struct {
+	mu      sync.RWMutex
+	jobs    map[string]*entry
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+}
+
+// New 创建一个新的调度器
+func New() *Scheduler {
+	return &Scheduler{jobs: make(map[string]*entry)}
+}
+
+// Register 注册一个任务；如果调度器已经启动，任务会立即开始调度
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Schedule == nil || job.Run == nil {
+		return fmt.Errorf("任务定义不完整: %q", job.Name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[job.Name]; exists {
+		return fmt.Errorf("任务 %q 已注册", job.Name)
+	}
+
+	e := &entry{
+		job:     job,
+		trigger: make(chan struct{}, 1),
+		status:  Status{Name: job.Name, Schedule: job.Schedule.String()},
+	}
+	s.jobs[job.Name] = e
+
+	if s.started {
+		s.startEntry(e)
+	}
+	return nil
+}
+
+// Start 启动所有已注册任务的调度循环
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.started = true
+
+	for _, e := range s.jobs {
+		s.startEntry(e)
+	}
+	log.Printf("调度器已启动，共 %d 个任务", len(s.jobs))
+}
+
+// Stop 取消所有任务并等待正在运行的任务退出
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	if !s.started {
+		s.mu.Unlock()
+		return
+	}
+	s.started = false
+	s.cancel()
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	log.Printf("调度器已停止")
+}
+
+// Trigger 立即在后台运行指定任务，不影响其常规调度
+func (s *Scheduler) Trigger(name string) error {
+	s.mu.RLock()
+	e, ok := s.jobs[name]
+	started := s.started
+	s.mu.RUnlock()
+
+	if !ok {
+		return ErrJobNotFound
+	}
+	if !started {
+		return fmt.Errorf("调度器未启动")
+	}
+
+	e.mu.Lock()
+	running := e.running
+	e.mu.Unlock()
+	if running {
+		return ErrJobRunning
+	}
+
+	select {
+	case e.trigger <- struct{}{}:
+	default:
+		// 已有一个待处理的触发请求
+	}
+	return nil
+}
+
+// List 返回所有任务的状态，按名称排序
+func (s *Scheduler) List() []Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	statuses := make([]Status, 0, len(s.jobs))
+	for _, e := range s.jobs {
+		e.mu.Lock()
+		statuses = append(statuses, e.status)
+		e.mu.Unlock()
+	}
+	sort.Slice(statuses, func(a, b int) bool {
+		return statuses[a].Name < statuses[b].Name
+	})
+	return statuses
+}
+
+// Get 返回单个任务的状态
+func (s *Scheduler) Get(name string) (Status, bool) {
+	s.mu.RLock()
+	e, ok := s.jobs[name]
+	s.mu.RUnlock()
+	if !ok {
+		return Status{}, false
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.status, true
+}
+
+// startEntry 为任务启动调度循环，调用方需持有 s.mu
+func (s *Scheduler) startEntry(e *entry) {
+	ctx := s.ctx
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.loop(ctx, e)
+	}()
+}
+
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	for {
+		next := e.job.Schedule.Next(time.Now())
+		if next.IsZero() {
+			log.Printf("任务 %s 没有下一次运行时间，停止调度", e.job.Name)
+			return
+		}
+
+		e.mu.Lock()
+		e.status.NextRun = next
+		e.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		case <-e.trigger:
+			timer.Stop()
+		}
+
+		s.execute(ctx, e)
+	}
+}
+
+// execute 运行一次任务，记录耗时和错误，并从 panic 中恢复
+func (s *Scheduler) execute(ctx context.Context, e *entry) {
+	e.mu.Lock()
+	if e.running {
+		e.mu.Unlock()
+		return
+	}
+	e.running = true
+	e.status.Running = true
+	e.mu.Unlock()
+
+	start := time.Now()
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		return e.job.Run(ctx)
+	}()
+	duration := time.Since(start)
+
+	e.mu.Lock()
+	e.running = false
+	e.status.Running = false
+	e.status.Runs++
+	e.status.LastRun = start
+	e.status.LastDuration = duration
+	if err != nil {
+		e.status.LastError = err.Error()
+	} else {
+		e.status.LastError = ""
+	}
+	e.mu.Unlock()
+
+	if err != nil {
+		log.Printf("任务 %s 运行失败 (耗时 %v): %v", e.job.Name, duration, err)
+	}
+}
+
+// Default 是全局调度器
+var Default = New()
+
+// Register 向全局调度器注册任务，注册失败时记录日志
+func Register(job Job) {
+	if err := Default.Register(job); err != nil {
+		log.Printf("注册任务失败: %v", err)
+	}
+}
+
+// Start 启动全局调度器
+func Start() {
+	Default.Start()
+}
+
+// Stop 停止全局调度器
+func Stop() {
+	Default.Stop()
+}
+
+// Trigger 立即运行全局调度器中的任务
+func Trigger(name string) error {
+	return Default.Trigger(name)
+}
+
+// List 返回全局调度器中所有任务的状态
+func List() []Status {
+	return Default.List()
+}
+
+// Get 返回全局调度器中单个任务的状态
+func Get(name string) (Status, bool) {
+	return Default.Get(name)
+}
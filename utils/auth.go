@@ -23,3 +23,16 @@ func CheckAuth(userID string, roles []string) bool {
 
 	return false
 }
+
+// IsReviewer 检查用户是否为审核员：管理员或拥有 review.reviewer_role_ids 中的身份组
+func IsReviewer(userID string, roles []string) bool {
+	if CheckAuth(userID, roles) {
+		return true
+	}
+	for _, role := range roles {
+		if slices.Contains(config.Cfg.Review.ReviewerRoleIDs, role) {
+			return true
+		}
+	}
+	return false
+}
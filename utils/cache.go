@@ -3,6 +3,8 @@ package utils
 import (
 	"amway/db"
 	"amway/model"
+	"amway/scheduler"
+	"context"
 	"log"
 	"sync"
 	"time"
@@ -20,8 +22,21 @@ var (
 )
 
 func init() {
-	go startCacheJanitor()
-	go startRateLimitJanitor()
+	// 清理过期缓存并自动拒绝超过审核期限（默认 24 小时）仍未审核的投稿
+	scheduler.Register(scheduler.Job{
+		Name:     "auto_reject_sweep",
+		Schedule: scheduler.Every(1 * time.Hour),
+		Run:      processExpiredSubmissions,
+	})
+	// 清理过期的投稿频率限制记录
+	scheduler.Register(scheduler.Job{
+		Name:     "rate_limit_cleanup",
+		Schedule: scheduler.Every(30 * time.Minute),
+		Run: func(ctx context.Context) error {
+			cleanExpiredRateLimit()
+			return nil
+		},
+	})
 }
 
 // AddToCache adds submission data to the cache and returns a unique ID.
@@ -49,34 +64,154 @@ func UpdateCache(id string, data model.SubmissionData) {
 	cacheMutex.Lock()
 	defer cacheMutex.Unlock()
 
-	// It's important to preserve the original creation time
+	// It's important to preserve the original creation time and review deadline state
 	if oldData, ok := submissionCache[id]; ok {
 		data.CreatedAt = oldData.CreatedAt
+		data.Deadline = oldData.Deadline
+		data.RemindersSent = oldData.RemindersSent
 		submissionCache[id] = data
 	}
 }
 
-// RemoveFromCache removes submission data from the cache by ID.
-func RemoveFromCache(id string) {
+// SnapshotCache returns a copy of all submission cache entries keyed by cache ID.
+func SnapshotCache() map[string]model.SubmissionData {
+	cacheMutex.RLock()
+	defer cacheMutex.RUnlock()
+
+	snapshot := make(map[string]model.SubmissionData, len(submissionCache))
+	for id, data := range submissionCache {
+		snapshot[id] = data
+	}
+	return snapshot
+}
+
+// SubmissionDeadline returns the time after which a pending submission is auto-rejected.
+func SubmissionDeadline(data model.SubmissionData) time.Time {
+	if !data.Deadline.IsZero() {
+		return data.Deadline
+	}
+	return data.CreatedAt.Add(cacheTTL)
+}
+
+// ExtendSubmissionDeadline pushes back the auto-rejection deadline of a cache entry
+// and returns the new deadline. It returns false if the entry no longer exists.
+func ExtendSubmissionDeadline(id string, by time.Duration) (time.Time, bool) {
 	cacheMutex.Lock()
 	defer cacheMutex.Unlock()
 
-	delete(submissionCache, id)
+	data, ok := submissionCache[id]
+	if !ok {
+		return time.Time{}, false
+	}
+	base := SubmissionDeadline(data)
+	if now := time.Now(); base.Before(now) {
+		base = now
+	}
+	data.Deadline = base.Add(by)
+	submissionCache[id] = data
+	return data.Deadline, true
+}
+
+// MarkRemindersSent records how many review reminder checkpoints have been announced for a cache entry.
+func MarkRemindersSent(id string, count int) {
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	if data, ok := submissionCache[id]; ok && data.RemindersSent < count {
+		data.RemindersSent = count
+		submissionCache[id] = data
+	}
 }
 
-// startCacheJanitor runs a background process to clean up expired cache entries
-// and automatically reject submissions that haven't been reviewed within 24 hours.
-func startCacheJanitor() {
-	ticker := time.NewTicker(1 * time.Hour) // Check every hour
-	defer ticker.Stop()
+// ActiveClaim returns the reviewer holding an unexpired claim on a cache entry.
+func ActiveClaim(data model.SubmissionData) (string, bool) {
+	if data.ClaimedBy == "" || time.Now().After(data.ClaimedUntil) {
+		return "", false
+	}
+	return data.ClaimedBy, true
+}
 
-	for range ticker.C {
-		processExpiredSubmissions()
+// ClaimSubmission marks a cache entry as being reviewed by userID for the given window.
+// An active claim by another reviewer is only taken over when override is set.
+// It returns the entry after the attempt and whether the claim was granted.
+func ClaimSubmission(id, userID string, window time.Duration, override bool) (model.SubmissionData, bool) {
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	data, ok := submissionCache[id]
+	if !ok {
+		return data, false
 	}
+	if holder, claimed := ActiveClaim(data); claimed && holder != userID && !override {
+		return data, false
+	}
+	data.ClaimedBy = userID
+	data.ClaimedUntil = time.Now().Add(window)
+	submissionCache[id] = data
+	return data, true
+}
+
+// ReleaseClaim clears the claim of a cache entry if it is held by userID.
+// It returns true if a claim was released.
+func ReleaseClaim(id, userID string) bool {
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	data, ok := submissionCache[id]
+	if !ok || data.ClaimedBy == "" || data.ClaimedBy != userID {
+		return false
+	}
+	data.ClaimedBy = ""
+	data.ClaimedUntil = time.Time{}
+	submissionCache[id] = data
+	return true
+}
+
+// ReleaseExpiredClaims clears all claims whose window has passed and returns the affected entries.
+func ReleaseExpiredClaims() map[string]model.SubmissionData {
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	released := make(map[string]model.SubmissionData)
+	now := time.Now()
+	for id, data := range submissionCache {
+		if data.ClaimedBy == "" || now.Before(data.ClaimedUntil) {
+			continue
+		}
+		data.ClaimedBy = ""
+		data.ClaimedUntil = time.Time{}
+		submissionCache[id] = data
+		released[id] = data
+	}
+	return released
+}
+
+// CacheSizes returns the number of entries in each in-memory cache, keyed by cache name.
+func CacheSizes() map[string]int {
+	cacheMutex.RLock()
+	submissions := len(submissionCache)
+	cacheMutex.RUnlock()
+
+	rateLimitMutex.RLock()
+	rateLimits := len(submissionRateLimit)
+	rateLimitMutex.RUnlock()
+
+	return map[string]int{
+		"submission": submissions,
+		"rate_limit": rateLimits,
+	}
+}
+
+// RemoveFromCache removes submission data from the cache by ID.
+func RemoveFromCache(id string) {
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	delete(submissionCache, id)
 }
 
 // processExpiredSubmissions handles expired cache entries and auto-rejection
-func processExpiredSubmissions() {
+func processExpiredSubmissions(ctx context.Context) error {
 	cacheMutex.Lock()
 	var expiredEntries []struct {
 		cacheID string
@@ -85,7 +220,7 @@ func processExpiredSubmissions() {
 
 	// First, collect all expired entries
 	for id, data := range submissionCache {
-		if time.Since(data.CreatedAt) > cacheTTL {
+		if time.Now().After(SubmissionDeadline(data)) {
 			expiredEntries = append(expiredEntries, struct {
 				cacheID string
 				data    model.SubmissionData
@@ -96,8 +231,12 @@ func processExpiredSubmissions() {
 
 	// Process each expired entry
 	for _, entry := range expiredEntries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		handleExpiredSubmission(entry.cacheID, entry.data)
 	}
+	return nil
 }
 
 // handleExpiredSubmission processes a single expired submission
@@ -128,7 +267,7 @@ func handleExpiredSubmission(cacheID string, data model.SubmissionData) {
 
 	// Only auto-reject if still pending
 	if submission.Status == "pending" {
-		log.Printf("Auto-rejecting expired submission %s after 24 hours", data.SubmissionID)
+		log.Printf("Auto-rejecting expired submission %s after its review deadline", data.SubmissionID)
 		autoRejectSubmission(submission)
 	} else {
 		log.Printf("Submission %s already processed (status: %s), removing from cache", data.SubmissionID, submission.Status)
@@ -193,16 +332,6 @@ func RecordSubmissionTime(userID string) {
 	submissionRateLimit[userID] = time.Now()
 }
 
-// startRateLimitJanitor runs a background process to clean up expired rate limit entries
-func startRateLimitJanitor() {
-	ticker := time.NewTicker(30 * time.Minute) // Check every 30 minutes
-	defer ticker.Stop()
-
-	for range ticker.C {
-		cleanExpiredRateLimit()
-	}
-}
-
 // cleanExpiredRateLimit removes expired rate limit entries
 func cleanExpiredRateLimit() {
 	rateLimitMutex.Lock()
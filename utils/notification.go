@@ -5,16 +5,19 @@ import (
 	"log"
 )
 
-// GlobalDiscordSession stores the Discord session for use in utils
-var GlobalDiscordSession interface{}
+// autoRejectionNotifier 由通知服务注入，utils 不直接依赖 Discord 会话
+var autoRejectionNotifier func(submission *model.Submission, reason string)
+
+// SetAutoRejectionNotifier registers the function used to notify users about automatic rejections.
+func SetAutoRejectionNotifier(fn func(submission *model.Submission, reason string)) {
+	autoRejectionNotifier = fn
+}
 
 // SendAutoRejectionDM sends a direct message to the user about automatic rejection
 func SendAutoRejectionDM(submission *model.Submission, reason string) {
-	// For now, just log the auto-rejection until we set up the proper session access
-	log.Printf("AUTO-REJECTION NOTIFICATION: Would send DM to user %s for submission %s with reason: %s", 
-		submission.UserID, submission.ID, reason)
-	log.Printf("Submission details - Title: %s, Content: %s", 
-		submission.RecommendTitle, submission.RecommendContent)
-	
-	// TODO: Implement actual DM sending when session access is properly configured
-}
\ No newline at end of file
+	if autoRejectionNotifier == nil {
+		log.Printf("No notifier registered, skipping auto-rejection DM to user %s for submission %s", submission.UserID, submission.ID)
+		return
+	}
+	autoRejectionNotifier(submission, reason)
+}
@@ -22,6 +22,10 @@ const (
 	Ban VoteType = "ban"
 	// Feature represents a vote to feature the submission.
 	Feature VoteType = "feature"
+	// Revise represents a vote to return the submission to its author for editing.
+	Revise VoteType = "revise"
+	// Warn represents a vote to reject the submission and warn its author.
+	Warn VoteType = "warn"
 )
 
 // Vote represents a single vote cast by an admin.
@@ -32,36 +36,172 @@ type Vote struct {
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// EventType describes what happened to a voter's vote.
+type EventType string
+
+const (
+	// EventCast records a voter's first vote in a round.
+	EventCast EventType = "cast"
+	// EventChange records a voter replacing their vote.
+	EventChange EventType = "change"
+	// EventRetract records a voter withdrawing their vote.
+	EventRetract EventType = "retract"
+)
+
+// Event is one entry in a session's vote history.
+type Event struct {
+	Event   EventType `json:"event"`
+	Round   int       `json:"round"` // Index of the review round, equal to the number of previous rounds at the time
+	VoterID string    `json:"voter_id"`
+	// Type and Reason are the vote after the event; for retractions they hold the withdrawn vote.
+	Type   VoteType `json:"type"`
+	Reason string   `json:"reason,omitempty"`
+	// PreviousType and PreviousReason hold the replaced vote of a change.
+	PreviousType   VoteType  `json:"previous_type,omitempty"`
+	PreviousReason string    `json:"previous_reason,omitempty"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
 // Session represents a voting session for a single submission.
 type Session struct {
 	VoteFileID   string `json:"vote_file_id"`
 	SubmissionID string `json:"submission_id"`
-	Votes        []Vote `json:"votes"`
+	// Votes is the current tally of the running round. It is derived from History and only stored
+	// so the file stays readable on its own.
+	Votes []Vote `json:"votes"`
+	// History keeps every cast, change and retraction of all rounds in order.
+	History []Event `json:"history,omitempty"`
+	// PreviousRounds keeps the votes of earlier review rounds when a submission is sent back for revision or reopened.
+	PreviousRounds [][]Vote `json:"previous_rounds,omitempty"`
+	// RoundOutcomes records how each previous round ended, aligned with PreviousRounds.
+	// Sessions written before outcomes were recorded only have revision rounds.
+	RoundOutcomes []string `json:"round_outcomes,omitempty"`
+}
+
+// Round outcomes recorded when a round is archived.
+const (
+	// OutcomeRevision means the round sent the submission back to its author for editing.
+	OutcomeRevision = "revision"
+	// OutcomeReopened means an admin reverted the round's decision and reopened the review.
+	OutcomeReopened = "reopened"
+)
+
+// StartNewRound archives the current votes with the outcome of their round and starts an empty round,
+// used when a submission returns to review.
+func (s *Session) StartNewRound(outcome string) {
+	s.seedHistory()
+	if len(s.Votes) > 0 {
+		for len(s.RoundOutcomes) < len(s.PreviousRounds) {
+			s.RoundOutcomes = append(s.RoundOutcomes, OutcomeRevision)
+		}
+		s.PreviousRounds = append(s.PreviousRounds, s.Votes)
+		s.RoundOutcomes = append(s.RoundOutcomes, outcome)
+	}
+	s.Votes = []Vote{}
+}
+
+// RoundOutcome returns how the previous round at index idx ended.
+func (s *Session) RoundOutcome(idx int) string {
+	if idx < len(s.RoundOutcomes) {
+		return s.RoundOutcomes[idx]
+	}
+	return OutcomeRevision
+}
+
+// AllVotes returns the votes of all rounds, oldest first.
+func (s *Session) AllVotes() []Vote {
+	var votes []Vote
+	for _, round := range s.PreviousRounds {
+		votes = append(votes, round...)
+	}
+	return append(votes, s.Votes...)
 }
 
-// AddVote adds a new vote to a session.
+// RoundHistory returns the events of the running round, oldest first.
+func (s *Session) RoundHistory() []Event {
+	round := len(s.PreviousRounds)
+	var events []Event
+	for _, e := range s.History {
+		if e.Round == round {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// seedHistory records the votes of sessions written before the history existed as cast events,
+// so the tally derived from the history matches what was stored.
+func (s *Session) seedHistory() {
+	if len(s.History) > 0 {
+		return
+	}
+	for idx, round := range s.PreviousRounds {
+		for _, v := range round {
+			s.History = append(s.History, Event{Event: EventCast, Round: idx, VoterID: v.VoterID, Type: v.Type, Reason: v.Reason, Timestamp: v.Timestamp})
+		}
+	}
+	for _, v := range s.Votes {
+		s.History = append(s.History, Event{Event: EventCast, Round: len(s.PreviousRounds), VoterID: v.VoterID, Type: v.Type, Reason: v.Reason, Timestamp: v.Timestamp})
+	}
+}
+
+// replay derives the tally of the running round from the history.
+// A changed vote keeps its position, so the order of first votes is preserved.
+func (s *Session) replay() {
+	votes := []Vote{}
+	for _, e := range s.RoundHistory() {
+		idx := -1
+		for i, v := range votes {
+			if v.VoterID == e.VoterID {
+				idx = i
+				break
+			}
+		}
+		switch e.Event {
+		case EventCast, EventChange:
+			v := Vote{VoterID: e.VoterID, Type: e.Type, Reason: e.Reason, Timestamp: e.Timestamp}
+			if idx >= 0 {
+				votes[idx] = v
+			} else {
+				votes = append(votes, v)
+			}
+		case EventRetract:
+			if idx >= 0 {
+				votes = append(votes[:idx], votes[idx+1:]...)
+			}
+		}
+	}
+	s.Votes = votes
+}
+
+// AddVote records a vote in the history. A second vote by the same voter is recorded as a change.
 func (s *Session) AddVote(vote Vote) {
-	// Check if the user has already voted
-	for i, v := range s.Votes {
+	s.seedHistory()
+	event := Event{Event: EventCast, Round: len(s.PreviousRounds), VoterID: vote.VoterID, Type: vote.Type, Reason: vote.Reason, Timestamp: vote.Timestamp}
+	for _, v := range s.Votes {
 		if v.VoterID == vote.VoterID {
-			s.Votes[i] = vote // Overwrite the existing vote
-			return
+			event.Event = EventChange
+			event.PreviousType = v.Type
+			event.PreviousReason = v.Reason
+			break
 		}
 	}
-	s.Votes = append(s.Votes, vote)
+	s.History = append(s.History, event)
+	s.replay()
 }
 
-// RemoveVote removes a vote from a session by voterID. Returns true if a vote was removed.
+// RemoveVote records the retraction of a voter's vote. Returns true if the voter had a vote to retract.
 func (s *Session) RemoveVote(voterID string) bool {
-	originalVoteCount := len(s.Votes)
-	var newVotes []Vote
+	s.seedHistory()
 	for _, v := range s.Votes {
 		if v.VoterID != voterID {
-			newVotes = append(newVotes, v)
+			continue
 		}
+		s.History = append(s.History, Event{Event: EventRetract, Round: len(s.PreviousRounds), VoterID: voterID, Type: v.Type, Reason: v.Reason, Timestamp: time.Now()})
+		s.replay()
+		return true
 	}
-	s.Votes = newVotes
-	return len(s.Votes) < originalVoteCount
+	return false
 }
 
 const voteDir = "data/votes"
@@ -105,6 +245,9 @@ func (m *Manager) LoadSession(voteFileID string) (*Session, error) {
 	if err := json.Unmarshal(data, &session); err != nil {
 		return nil, err
 	}
+	if len(session.History) > 0 {
+		session.replay()
+	}
 	return &session, nil
 }
 
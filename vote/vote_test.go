@@ -0,0 +1,154 @@
+package vote
+
+import (
+	"testing"
+	"time"
+)
+
+func castAt(voterID string, voteType VoteType, minute int) Vote {
+	return Vote{VoterID: voterID, Type: voteType, Timestamp: time.Date(2025, 1, 1, 0, minute, 0, 0, time.UTC)}
+}
+
+func assertVotes(t *testing.T, got []Vote, want ...Vote) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d votes %+v, want %d", len(got), got, len(want))
+	}
+	for idx := range want {
+		if got[idx].VoterID != want[idx].VoterID || got[idx].Type != want[idx].Type {
+			t.Errorf("vote %d = %s/%s, want %s/%s", idx, got[idx].VoterID, got[idx].Type, want[idx].VoterID, want[idx].Type)
+		}
+	}
+}
+
+func assertEvents(t *testing.T, got []Event, want ...EventType) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d events %+v, want %d", len(got), got, len(want))
+	}
+	for idx := range want {
+		if got[idx].Event != want[idx] {
+			t.Errorf("event %d = %s, want %s", idx, got[idx].Event, want[idx])
+		}
+	}
+}
+
+func TestAddVoteChangeKeepsPosition(t *testing.T) {
+	s := &Session{}
+	s.AddVote(castAt("a", Pass, 0))
+	s.AddVote(castAt("b", Reject, 1))
+	s.AddVote(castAt("a", Feature, 2))
+
+	assertVotes(t, s.Votes, castAt("a", Feature, 2), castAt("b", Reject, 1))
+	assertEvents(t, s.History, EventCast, EventCast, EventChange)
+	change := s.History[2]
+	if change.PreviousType != Pass || change.Type != Feature {
+		t.Errorf("change recorded %s -> %s, want %s -> %s", change.PreviousType, change.Type, Pass, Feature)
+	}
+}
+
+func TestRemoveVote(t *testing.T) {
+	s := &Session{}
+	s.AddVote(castAt("a", Pass, 0))
+	s.AddVote(castAt("b", Reject, 1))
+
+	if !s.RemoveVote("a") {
+		t.Fatal("RemoveVote(a) = false, want true")
+	}
+	assertVotes(t, s.Votes, castAt("b", Reject, 1))
+	assertEvents(t, s.History, EventCast, EventCast, EventRetract)
+	if s.History[2].Type != Pass {
+		t.Errorf("retraction holds %s, want the withdrawn %s", s.History[2].Type, Pass)
+	}
+
+	if s.RemoveVote("a") {
+		t.Error("RemoveVote(a) twice = true, want false")
+	}
+	assertEvents(t, s.History, EventCast, EventCast, EventRetract)
+
+	// A voter who retracted votes again as a fresh cast at the end of the tally
+	s.AddVote(castAt("a", Reject, 3))
+	assertVotes(t, s.Votes, castAt("b", Reject, 1), castAt("a", Reject, 3))
+	assertEvents(t, s.History, EventCast, EventCast, EventRetract, EventCast)
+}
+
+func TestLegacySessionIsSeeded(t *testing.T) {
+	// Sessions written before the history existed only have the tallies of each round
+	s := &Session{
+		PreviousRounds: [][]Vote{{castAt("a", Revise, 0)}},
+		Votes:          []Vote{castAt("a", Pass, 10), castAt("b", Pass, 11)},
+	}
+	s.AddVote(castAt("b", Reject, 12))
+
+	assertEvents(t, s.History, EventCast, EventCast, EventCast, EventChange)
+	wantRounds := []int{0, 1, 1, 1}
+	for idx, e := range s.History {
+		if e.Round != wantRounds[idx] {
+			t.Errorf("event %d in round %d, want %d", idx, e.Round, wantRounds[idx])
+		}
+	}
+	assertVotes(t, s.Votes, castAt("a", Pass, 10), castAt("b", Reject, 12))
+	assertVotes(t, s.PreviousRounds[0], castAt("a", Revise, 0))
+	if got := s.RoundOutcome(0); got != OutcomeRevision {
+		t.Errorf("RoundOutcome(0) = %q, want %q", got, OutcomeRevision)
+	}
+}
+
+func TestStartNewRound(t *testing.T) {
+	s := &Session{}
+	s.AddVote(castAt("a", Revise, 0))
+	s.StartNewRound(OutcomeRevision)
+	s.AddVote(castAt("a", Ban, 1))
+	s.StartNewRound(OutcomeReopened)
+	s.AddVote(castAt("b", Pass, 2))
+	s.AddVote(castAt("a", Pass, 3))
+
+	if len(s.PreviousRounds) != 2 {
+		t.Fatalf("got %d previous rounds, want 2", len(s.PreviousRounds))
+	}
+	assertVotes(t, s.PreviousRounds[0], castAt("a", Revise, 0))
+	assertVotes(t, s.PreviousRounds[1], castAt("a", Ban, 1))
+	if s.RoundOutcome(0) != OutcomeRevision || s.RoundOutcome(1) != OutcomeReopened {
+		t.Errorf("round outcomes = %v, want [%s %s]", s.RoundOutcomes, OutcomeRevision, OutcomeReopened)
+	}
+
+	// Voting again in a new round is a cast, not a change of the archived vote
+	assertVotes(t, s.Votes, castAt("b", Pass, 2), castAt("a", Pass, 3))
+	assertEvents(t, s.RoundHistory(), EventCast, EventCast)
+	if got := len(s.AllVotes()); got != 4 {
+		t.Errorf("AllVotes() has %d votes, want 4", got)
+	}
+
+	// Archiving an empty round records nothing
+	s.StartNewRound(OutcomeReopened)
+	s.StartNewRound(OutcomeReopened)
+	if len(s.PreviousRounds) != 3 || len(s.RoundOutcomes) != 3 {
+		t.Errorf("got %d rounds and %d outcomes after an empty round, want 3 and 3", len(s.PreviousRounds), len(s.RoundOutcomes))
+	}
+}
+
+func TestManagerReplaysHistory(t *testing.T) {
+	m := &Manager{path: t.TempDir()}
+	s, err := m.LoadSession("test")
+	if err != nil {
+		t.Fatalf("LoadSession() error = %v", err)
+	}
+	s.AddVote(castAt("a", Pass, 0))
+	s.StartNewRound(OutcomeReopened)
+	s.AddVote(castAt("a", Reject, 1))
+	s.AddVote(castAt("b", Pass, 2))
+	s.RemoveVote("a")
+	// A stale tally in the file is replaced by the one derived from the history
+	s.Votes = []Vote{castAt("c", Ban, 3)}
+	if err := m.SaveSession(s); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+
+	loaded, err := m.LoadSession("test")
+	if err != nil {
+		t.Fatalf("LoadSession() error = %v", err)
+	}
+	assertVotes(t, loaded.Votes, castAt("b", Pass, 2))
+	assertVotes(t, loaded.PreviousRounds[0], castAt("a", Pass, 0))
+	assertEvents(t, loaded.History, EventCast, EventCast, EventCast, EventRetract)
+}